@@ -1,8 +1,6 @@
 package account
 
 import (
-	"context"
-	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"lisk/config"
@@ -13,20 +11,20 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"golang.org/x/sync/errgroup"
 )
 
 type Account struct {
 	Address             common.Address
-	PrivateKey          *ecdsa.PrivateKey
-	RawPK               string
+	Signer              Signer
+	RawPK               string // line of privateKeys.txt, empty for keystore and vault keys
 	LastSwaps           []models.SwapPair
 	WrapHistory         models.WrapHistory
 	WrapRange           models.WrapRange
 	SwapRange           models.SwapRange
 	LiquidityState      *models.LiquidityState
 	Stats               map[string]int
+	Retries             map[string]int              // retried actions per module
+	GasSpend            map[string]*models.GasSpend // fees of mined transactions per module
 	Mu                  sync.Mutex
 	ActionsCount        int
 	ActionsTime         int
@@ -34,37 +32,22 @@ type Account struct {
 	Proxy               string
 }
 
-func AccsFactory(privateKeys, proxys []string, cfg *config.Config, selectedModule string) ([]*Account, error) {
-	if len(privateKeys) == 0 {
+func AccsFactory(signers []Signer, proxys []string, wallets map[common.Address]WalletMeta, cfg *config.Config, selectedModule string) ([]*Account, error) {
+	if len(signers) == 0 {
 		return nil, errors.New("privateKeys list is empty")
 	}
 
 	if selectedModule == "AirdropStatus" {
 		var accs []*Account
-		for i, keyOrAddress := range privateKeys {
-			var address common.Address
-
-			// Проверяем, является ли переданное значение приватным ключом
-			privKey, err := crypto.HexToECDSA(keyOrAddress)
-			if err == nil {
-				// Если это приватный ключ, извлекаем адрес из публичного ключа
-				publicKey := privKey.Public().(*ecdsa.PublicKey)
-				address = crypto.PubkeyToAddress(*publicKey)
-			} else {
-				// Если это не приватный ключ, трактуем его как обычный адрес
-				address = common.HexToAddress(keyOrAddress)
-			}
-
-			proxy := ""
-			if len(proxys) > 0 {
-				proxy = proxys[i]
-			}
-
+		for i, signer := range signers {
 			accs = append(accs, &Account{
-				Address:      address,
+				Address:      signer.Address(),
+				Signer:       signer,
 				ActionsCount: 1,
 				Stats:        make(map[string]int),
-				Proxy:        proxy,
+				Retries:      make(map[string]int),
+				GasSpend:     make(map[string]*models.GasSpend),
+				Proxy:        proxyFor(i, signer, proxys, wallets),
 			})
 		}
 		return accs, nil
@@ -75,78 +58,55 @@ func AccsFactory(privateKeys, proxys []string, cfg *config.Config, selectedModul
 		return nil, fmt.Errorf("failed to prepare ranges: %w", err)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	g, ctx := errgroup.WithContext(ctx)
-
-	var (
-		accs []*Account
-		mu   sync.Mutex
-	)
-
-	accs = make([]*Account, 0, len(privateKeys))
-
-	for i, pk := range privateKeys {
-		pk := pk
-
-		g.Go(func() error {
-			if ctx.Err() != nil {
-				return ctx.Err()
-			}
-
-			privateKey, err := utils.ParsePrivateKey(pk)
-			if err != nil {
-				return err
-			}
-
-			publicAddr, err := utils.DeriveAddress(privateKey)
-			if err != nil {
-				return err
-			}
-
-			if cfg.ActionCounts == 0 {
-				return fmt.Errorf("0 actions count, check config")
-			}
-
-			var proxy string
-			if len(proxys) > i {
-				proxy = proxys[i]
-			} else {
-				proxy = ""
-			}
-
-			account := &Account{
-				Address:             publicAddr,
-				PrivateKey:          privateKey,
-				RawPK:               pk,
-				LastSwaps:           []models.SwapPair{},
-				WrapHistory:         models.WrapHistory{},
-				WrapRange:           *wrapRange,
-				SwapRange:           *swapRange,
-				LiquidityState:      &models.LiquidityState{},
-				ActionsCount:        cfg.ActionCounts,
-				ActionsTime:         cfg.MaxActionsTime,
-				BalancePercentUsage: cfg.OkuPercentUsage,
-				Stats:               make(map[string]int),
-				Proxy:               proxy,
-			}
-
-			mu.Lock()
-			accs = append(accs, account)
-			mu.Unlock()
-
-			return nil
-		})
+	if cfg.ActionCounts == 0 {
+		return nil, fmt.Errorf("0 actions count, check config")
 	}
 
-	if err := g.Wait(); err != nil {
-		return nil, err
+	accs := make([]*Account, 0, len(signers))
+
+	for i, signer := range signers {
+		if _, watchOnly := signer.(*AddressSigner); watchOnly {
+			return nil, fmt.Errorf("%s: %w, module %s sends transactions", signer.Address().Hex(), errWatchOnly, selectedModule)
+		}
+
+		account := &Account{
+			Address:             signer.Address(),
+			Signer:              signer,
+			LastSwaps:           []models.SwapPair{},
+			WrapHistory:         models.WrapHistory{},
+			WrapRange:           *wrapRange,
+			SwapRange:           *swapRange,
+			LiquidityState:      &models.LiquidityState{},
+			ActionsCount:        cfg.ActionCounts,
+			ActionsTime:         cfg.MaxActionsTime,
+			BalancePercentUsage: cfg.OkuPercentUsage,
+			Stats:               make(map[string]int),
+			Retries:             make(map[string]int),
+			GasSpend:            make(map[string]*models.GasSpend),
+			Proxy:               proxyFor(i, signer, proxys, wallets),
+		}
+		if keySigner, ok := signer.(*KeySigner); ok {
+			account.RawPK = keySigner.line
+		}
+
+		accs = append(accs, account)
 	}
 
 	return accs, nil
 }
 
+// proxyFor returns the proxy of the wallets file or the line of proxy.txt
+// with the same index as the key.
+func proxyFor(i int, signer Signer, proxys []string, wallets map[common.Address]WalletMeta) string {
+	if meta, exists := wallets[signer.Address()]; exists && meta.Proxy != "" {
+		return meta.Proxy
+	}
+	if len(proxys) > i {
+		return proxys[i]
+	}
+	return ""
+}
+
 func prepareRanges(cfg *config.Config) (*models.WrapRange, *models.SwapRange, error) {
 	var wrapRange models.WrapRange
 	if err := utils.ConvertRangeAmount(cfg.WrapMinAmount, cfg.WrapMaxAmount, 18, globals.NULL, &wrapRange); err != nil {
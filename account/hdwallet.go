@@ -0,0 +1,137 @@
+package account
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// DefaultHDPath is the BIP-44 path of MetaMask and most wallets, {i} is the
+// account index. Ledger Live uses m/44'/60'/{i}'/0/0.
+const DefaultHDPath = "m/44'/60'/0'/0/{i}"
+
+// NewMnemonic generates a 24 word BIP-39 mnemonic.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate entropy: %w", err)
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// DeriveKeys derives count private keys from the mnemonic, starting with the
+// account index from. The index replaces {i} in path; a path without {i}
+// gets the index as the last component.
+func DeriveKeys(mnemonic, passphrase, path string, from, count int) ([]*ecdsa.PrivateKey, error) {
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic: unknown word or wrong checksum")
+	}
+	if from < 0 || count <= 0 {
+		return nil, fmt.Errorf("invalid index range: from %d, count %d", from, count)
+	}
+
+	if path == "" {
+		path = DefaultHDPath
+	}
+	if !strings.Contains(path, "{i}") {
+		path = strings.TrimSuffix(path, "/") + "/{i}"
+	}
+
+	master, err := newMasterNode(bip39.NewSeed(mnemonic, passphrase))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*ecdsa.PrivateKey, 0, count)
+	for index := from; index < from+count; index++ {
+		indexPath := strings.ReplaceAll(path, "{i}", strconv.Itoa(index))
+		derivationPath, err := accounts.ParseDerivationPath(indexPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %s: %w", indexPath, err)
+		}
+
+		node, err := master.derive(derivationPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", indexPath, err)
+		}
+
+		key, err := crypto.ToECDSA(node.key.FillBytes(make([]byte, 32)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", indexPath, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+const hardenedOffset = 0x80000000
+
+// hdNode is an extended private key of BIP-32.
+type hdNode struct {
+	key       *big.Int
+	chainCode []byte
+}
+
+// newMasterNode returns the master key of a BIP-32 seed.
+func newMasterNode(seed []byte) (hdNode, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	master := hdNode{key: new(big.Int).SetBytes(sum[:32]), chainCode: sum[32:]}
+	if master.key.Sign() == 0 || master.key.Cmp(crypto.S256().Params().N) >= 0 {
+		return hdNode{}, fmt.Errorf("invalid master key, use another mnemonic")
+	}
+	return master, nil
+}
+
+func (n hdNode) derive(path accounts.DerivationPath) (hdNode, error) {
+	node := n
+	for _, component := range path {
+		var err error
+		if node, err = node.child(component); err != nil {
+			return hdNode{}, err
+		}
+	}
+	return node, nil
+}
+
+func (n hdNode) child(index uint32) (hdNode, error) {
+	data := make([]byte, 0, 37)
+	if index >= hardenedOffset {
+		data = append(data, 0)
+		data = append(data, n.key.FillBytes(make([]byte, 32))...)
+	} else {
+		x, y := crypto.S256().ScalarBaseMult(n.key.FillBytes(make([]byte, 32)))
+		data = append(data, crypto.CompressPubkey(&ecdsa.PublicKey{Curve: crypto.S256(), X: x, Y: y})...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, n.chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+
+	curveN := crypto.S256().Params().N
+	tweak := new(big.Int).SetBytes(sum[:32])
+	if tweak.Cmp(curveN) >= 0 {
+		return hdNode{}, fmt.Errorf("invalid child key %d, use another index", index)
+	}
+	key := tweak.Add(tweak, n.key)
+	key.Mod(key, curveN)
+	if key.Sign() == 0 {
+		return hdNode{}, fmt.Errorf("invalid child key %d, use another index", index)
+	}
+
+	return hdNode{key: key, chainCode: sum[32:]}, nil
+}
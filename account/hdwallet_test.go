@@ -0,0 +1,92 @@
+package account
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestDeriveKeys(t *testing.T) {
+	tests := []struct {
+		mnemonic string
+		path     string
+		from     int
+		want     []string
+	}{
+		{
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			want:     []string{"0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		},
+		{
+			// Default accounts of Hardhat and Anvil.
+			mnemonic: "test test test test test test test test test test test junk",
+			path:     "m/44'/60'/0'/0",
+			want: []string{
+				"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+				"0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+				"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC",
+			},
+		},
+		{
+			mnemonic: "test test test test test test test test test test test junk",
+			from:     2,
+			want:     []string{"0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"},
+		},
+	}
+
+	for _, tt := range tests {
+		keys, err := DeriveKeys(tt.mnemonic, "", tt.path, tt.from, len(tt.want))
+		if err != nil {
+			t.Fatalf("DeriveKeys(%q): %v", tt.path, err)
+		}
+		for i, key := range keys {
+			if got := crypto.PubkeyToAddress(key.PublicKey).Hex(); got != tt.want[i] {
+				t.Errorf("%s index %d: got %s, want %s", tt.mnemonic, tt.from+i, got, tt.want[i])
+			}
+		}
+	}
+}
+
+// TestHDNodeVector1 checks the private keys of BIP-32 test vector 1.
+func TestHDNodeVector1(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := newMasterNode(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		key  string
+		code string
+	}{
+		{"m", "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35", "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508"},
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea", "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368", "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca", "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4", "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8", "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e"},
+	}
+
+	for _, tt := range tests {
+		var path accounts.DerivationPath
+		if tt.path != "m" {
+			if path, err = accounts.ParseDerivationPath(tt.path); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		node, err := master.derive(path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		if got := hex.EncodeToString(node.key.FillBytes(make([]byte, 32))); got != tt.key {
+			t.Errorf("%s key: got %s, want %s", tt.path, got, tt.key)
+		}
+		if got := hex.EncodeToString(node.chainCode); got != tt.code {
+			t.Errorf("%s chain code: got %s, want %s", tt.path, got, tt.code)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"lisk/config"
+	"lisk/logger"
+	"lisk/utils"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+const (
+	KeySourceFile     = "file"
+	KeySourceKeystore = "keystore"
+	KeySourceVault    = "vault"
+	KeySourceRemote   = "remote"
+	KeySourceMnemonic = "mnemonic"
+)
+
+const (
+	defaultPassphraseEnv         = "LISK_PASSPHRASE"
+	defaultMnemonicEnv           = "LISK_MNEMONIC"
+	defaultMnemonicPassphraseEnv = "LISK_MNEMONIC_PASSPHRASE"
+)
+
+// LoadSigners loads the account keys from the configured source.
+func LoadSigners(source config.KeySource) ([]Signer, error) {
+	switch source.Type {
+	case "", KeySourceFile:
+		return loadKeyFile(source.Path)
+	case KeySourceKeystore:
+		return loadKeystore(source)
+	case KeySourceVault:
+		return loadVault(source)
+	case KeySourceRemote:
+		return loadRemote(source)
+	case KeySourceMnemonic:
+		return loadMnemonic(source)
+	default:
+		return nil, fmt.Errorf("unknown key source %q, expected file, keystore, vault, remote or mnemonic", source.Type)
+	}
+}
+
+func loadKeyFile(path string) ([]Signer, error) {
+	var (
+		lines []string
+		err   error
+	)
+	if path == "" {
+		lines, err = utils.GetPrivateKeys()
+	} else {
+		lines, err = utils.FileReader(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return parseKeyLines(lines)
+}
+
+func parseKeyLines(lines []string) ([]Signer, error) {
+	signers := make([]Signer, 0, len(lines))
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		signer, err := parseKeyLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("key %d: %w", i+1, err)
+		}
+		signers = append(signers, signer)
+	}
+
+	if len(signers) == 0 {
+		return nil, fmt.Errorf("no keys found")
+	}
+	return signers, nil
+}
+
+// loadKeystore decrypts V3 keystore files. Path is a single file or a
+// directory, every file of the directory is expected to be a keystore.
+func loadKeystore(source config.KeySource) ([]Signer, error) {
+	path := source.Path
+	if path == "" {
+		path = utils.GetPath("keystore")
+	}
+
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to open keystore: %w", err)
+	} else if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore directory: %w", err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			if !entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no keystore files in %s", path)
+	}
+
+	passphrase, err := readPassphrase(source, fmt.Sprintf("Passphrase for %d keystore file(s):", len(files)))
+	if err != nil {
+		return nil, err
+	}
+
+	// Keystores are decrypted one by one: scrypt of a standard keystore takes
+	// 256 MB of memory.
+	signers := make([]Signer, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read keystore %s: %w", file, err)
+		}
+
+		key, err := keystore.DecryptKey(data, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unlock keystore %s: %w", filepath.Base(file), err)
+		}
+		signers = append(signers, NewKeySigner(key.PrivateKey))
+	}
+
+	logger.GlobalLogger.Infof("Unlocked %d keystore file(s)", len(signers))
+	return signers, nil
+}
+
+func loadVault(source config.KeySource) ([]Signer, error) {
+	path := source.Path
+	if path == "" {
+		path = utils.GetPath("vault")
+	}
+
+	passphrase, err := readPassphrase(source, "Vault passphrase:")
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := ReadVault(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	signers, err := parseKeyLines(keys)
+	if err != nil {
+		return nil, fmt.Errorf("vault %s: %w", path, err)
+	}
+
+	logger.GlobalLogger.Infof("Unlocked vault with %d key(s)", len(signers))
+	return signers, nil
+}
+
+func loadRemote(source config.KeySource) ([]Signer, error) {
+	signers, err := DialRemoteSigners(context.Background(), source.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.GlobalLogger.Infof("Remote signer provides %d account(s)", len(signers))
+	return signers, nil
+}
+
+func loadMnemonic(source config.KeySource) ([]Signer, error) {
+	mnemonic, err := ReadMnemonic(source)
+	if err != nil {
+		return nil, err
+	}
+
+	count := source.IndexCount
+	if count == 0 {
+		count = 1
+	}
+
+	keys, err := DeriveKeys(mnemonic, MnemonicPassphrase(source), source.HDPath, source.IndexFrom, count)
+	if err != nil {
+		return nil, err
+	}
+
+	signers := make([]Signer, 0, len(keys))
+	for _, key := range keys {
+		signers = append(signers, NewKeySigner(key))
+	}
+
+	logger.GlobalLogger.Infof("Derived %d account(s) from the mnemonic, indexes %d-%d", len(signers), source.IndexFrom, source.IndexFrom+count-1)
+	return signers, nil
+}
+
+// ReadMnemonic takes the mnemonic from the environment variable of the source
+// and asks for it when the variable is not set.
+func ReadMnemonic(source config.KeySource) (string, error) {
+	env := source.MnemonicEnv
+	if env == "" {
+		env = defaultMnemonicEnv
+	}
+
+	if mnemonic, exists := os.LookupEnv(env); exists {
+		return mnemonic, nil
+	}
+
+	return utils.PromptPassphrase("Mnemonic:")
+}
+
+// MnemonicPassphrase returns the optional BIP-39 passphrase. It is never asked
+// for: an unset variable means no passphrase.
+func MnemonicPassphrase(source config.KeySource) string {
+	env := source.MnemonicPassphraseEnv
+	if env == "" {
+		env = defaultMnemonicPassphraseEnv
+	}
+	return os.Getenv(env)
+}
+
+// readPassphrase takes the passphrase from the environment variable of the
+// source and asks for it when the variable is not set.
+func readPassphrase(source config.KeySource, prompt string) (string, error) {
+	env := source.PassphraseEnv
+	if env == "" {
+		env = defaultPassphraseEnv
+	}
+
+	if passphrase, exists := os.LookupEnv(env); exists {
+		return passphrase, nil
+	}
+
+	return utils.PromptPassphrase(prompt)
+}
+
+// ReadNewPassphrase returns the passphrase for a new encrypted file: the
+// environment variable or a prompt with confirmation.
+func ReadNewPassphrase(source config.KeySource) (string, error) {
+	env := source.PassphraseEnv
+	if env == "" {
+		env = defaultPassphraseEnv
+	}
+
+	if passphrase, exists := os.LookupEnv(env); exists {
+		return passphrase, nil
+	}
+
+	passphrase, err := utils.PromptPassphrase("New passphrase:")
+	if err != nil {
+		return "", err
+	}
+	confirm, err := utils.PromptPassphrase("Repeat passphrase:")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != confirm {
+		return "", fmt.Errorf("passphrases do not match")
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("empty passphrase")
+	}
+	return passphrase, nil
+}
@@ -0,0 +1,199 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"lisk/retry"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const remoteSignerTimeout = 30 * time.Second
+
+// RemoteSigner sends transactions to an external signer (Web3Signer, Clef or
+// any server with the eth_signTransaction JSON-RPC method). The private key
+// stays on the signer, the bot only sees the signed transaction.
+type RemoteSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// signTxArgs are the parameters of eth_signTransaction.
+type signTxArgs struct {
+	From                 common.Address  `json:"from"`
+	To                   *common.Address `json:"to,omitempty"`
+	Gas                  hexutil.Uint64  `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	Value                *hexutil.Big    `json:"value"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	Data                 hexutil.Bytes   `json:"data"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+}
+
+// DialRemoteSigners connects to the signer at url and returns a signer for
+// every address of its eth_accounts.
+func DialRemoteSigners(ctx context.Context, url string) ([]Signer, error) {
+	if url == "" {
+		return nil, fmt.Errorf("remote signer url is empty")
+	}
+
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote signer: %w", err)
+	}
+
+	var addresses []common.Address
+	callCtx, cancel := context.WithTimeout(ctx, remoteSignerTimeout)
+	defer cancel()
+	if err := client.CallContext(callCtx, &addresses, "eth_accounts"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("remote signer eth_accounts: %w", err)
+	}
+	if len(addresses) == 0 {
+		client.Close()
+		return nil, fmt.Errorf("remote signer has no accounts")
+	}
+
+	signers := make([]Signer, 0, len(addresses))
+	for _, address := range addresses {
+		signers = append(signers, &RemoteSigner{client: client, address: address})
+	}
+	return signers, nil
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// SignTx asks the remote signer to sign tx and checks that the returned
+// transaction is the one that was requested and is signed by the address.
+func (s *RemoteSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	args := signTxArgs{
+		From:    s.address,
+		To:      tx.To(),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   (*hexutil.Big)(tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Data:    tx.Data(),
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+	default:
+		return nil, fmt.Errorf("remote signer: unsupported transaction type %d", tx.Type())
+	}
+
+	var result json.RawMessage
+	_, err := retry.RPC.Do(ctx, "eth_signTransaction", func() error {
+		callCtx, cancel := context.WithTimeout(ctx, remoteSignerTimeout)
+		defer cancel()
+		return s.client.CallContext(callCtx, &result, "eth_signTransaction", args)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("remote signer %s: %w", s.address.Hex(), err)
+	}
+
+	raw, err := decodeSignResult(result)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer %s: %w", s.address.Hex(), err)
+	}
+
+	signedTx := new(types.Transaction)
+	if err := signedTx.UnmarshalBinary(raw); err != nil {
+		return nil, fmt.Errorf("remote signer %s: invalid signed transaction: %w", s.address.Hex(), err)
+	}
+	if err := checkSignedTx(tx, signedTx, s.address, chainID); err != nil {
+		return nil, fmt.Errorf("remote signer %s: %w", s.address.Hex(), err)
+	}
+
+	return signedTx, nil
+}
+
+// SignTypedData signs an EIP-712 message with eth_signTypedData and checks
+// that the signature belongs to the address.
+func (s *RemoteSigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+
+	var signature hexutil.Bytes
+	callCtx, cancel := context.WithTimeout(ctx, remoteSignerTimeout)
+	defer cancel()
+	if err := s.client.CallContext(callCtx, &signature, "eth_signTypedData", s.address, data); err != nil {
+		return nil, fmt.Errorf("remote signer %s: %w", s.address.Hex(), err)
+	}
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("remote signer %s: invalid signature length %d", s.address.Hex(), len(signature))
+	}
+
+	recoverable := append([]byte{}, signature...)
+	if recoverable[crypto.RecoveryIDOffset] >= 27 {
+		recoverable[crypto.RecoveryIDOffset] -= 27
+	}
+	publicKey, err := crypto.SigToPub(hash, recoverable)
+	if err != nil || crypto.PubkeyToAddress(*publicKey) != s.address {
+		return nil, fmt.Errorf("remote signer %s: typed data signature does not match the address", s.address.Hex())
+	}
+
+	signature[crypto.RecoveryIDOffset] = recoverable[crypto.RecoveryIDOffset] + 27
+	return signature, nil
+}
+
+// decodeSignResult accepts the raw transaction as a hex string (Web3Signer)
+// or as {"raw": ..., "tx": ...} (geth, Clef).
+func decodeSignResult(result json.RawMessage) ([]byte, error) {
+	var raw hexutil.Bytes
+	if err := json.Unmarshal(result, &raw); err == nil {
+		return raw, nil
+	}
+
+	var object struct {
+		Raw hexutil.Bytes `json:"raw"`
+	}
+	if err := json.Unmarshal(result, &object); err != nil || len(object.Raw) == 0 {
+		return nil, fmt.Errorf("unexpected eth_signTransaction result %s", strings.TrimSpace(string(result)))
+	}
+	return object.Raw, nil
+}
+
+// checkSignedTx makes sure the signer did not change the transaction.
+func checkSignedTx(requested, signed *types.Transaction, from common.Address, chainID *big.Int) error {
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if sender != from {
+		return fmt.Errorf("transaction is signed by %s", sender.Hex())
+	}
+
+	sameTo := (requested.To() == nil) == (signed.To() == nil) &&
+		(requested.To() == nil || *requested.To() == *signed.To())
+	if !sameTo ||
+		signed.Nonce() != requested.Nonce() ||
+		signed.Gas() != requested.Gas() ||
+		signed.Value().Cmp(requested.Value()) != 0 ||
+		signed.GasFeeCap().Cmp(requested.GasFeeCap()) != 0 ||
+		signed.GasTipCap().Cmp(requested.GasTipCap()) != 0 ||
+		signed.ChainId().Cmp(chainID) != 0 ||
+		string(signed.Data()) != string(requested.Data()) {
+		return fmt.Errorf("signed transaction %s differs from the requested one", signed.Hash().Hex())
+	}
+
+	return nil
+}
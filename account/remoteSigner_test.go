@@ -0,0 +1,132 @@
+package account
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// stubSigner is a local eth_accounts/eth_signTransaction server. It reports
+// address and signs with key, so a key of another address makes it sign for
+// the wrong account.
+type stubSigner struct {
+	address common.Address
+	key     *ecdsa.PrivateKey
+	fail    bool
+}
+
+func (s *stubSigner) Accounts() []common.Address {
+	return []common.Address{s.address}
+}
+
+func (s *stubSigner) SignTransaction(args signTxArgs) (hexutil.Bytes, error) {
+	if s.fail {
+		return nil, errors.New("account is locked")
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   args.ChainID.ToInt(),
+		Nonce:     uint64(args.Nonce),
+		GasTipCap: args.MaxPriorityFeePerGas.ToInt(),
+		GasFeeCap: args.MaxFeePerGas.ToInt(),
+		Gas:       uint64(args.Gas),
+		To:        args.To,
+		Value:     args.Value.ToInt(),
+		Data:      args.Data,
+	})
+	signed, err := types.SignTx(tx, types.LatestSignerForChainID(args.ChainID.ToInt()), s.key)
+	if err != nil {
+		return nil, err
+	}
+	return signed.MarshalBinary()
+}
+
+func newStubSigner(t *testing.T, stub *stubSigner) Signer {
+	t.Helper()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", stub); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+
+	signers, err := DialRemoteSigners(context.Background(), httpServer.URL)
+	if err != nil {
+		t.Fatalf("DialRemoteSigners: %v", err)
+	}
+	if len(signers) != 1 || signers[0].Address() != stub.address {
+		t.Fatalf("eth_accounts: got %v, want [%s]", signers, stub.address.Hex())
+	}
+	return signers[0]
+}
+
+func testTx() *types.Transaction {
+	to := common.HexToAddress("0x4200000000000000000000000000000000000006")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   big.NewInt(1135),
+		Nonce:     7,
+		GasTipCap: big.NewInt(1000),
+		GasFeeCap: big.NewInt(2000000),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1),
+		Data:      []byte{0xd0, 0xe3, 0x0d, 0xb0},
+	})
+}
+
+func TestRemoteSignerSignTx(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	signer := newStubSigner(t, &stubSigner{address: address, key: key})
+
+	tx := testTx()
+	signed, err := signer.SignTx(context.Background(), tx, tx.ChainId())
+	if err != nil {
+		t.Fatalf("SignTx: %v", err)
+	}
+
+	sender, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), signed)
+	if err != nil || sender != address {
+		t.Fatalf("sender = %s, %v; want %s", sender.Hex(), err, address.Hex())
+	}
+	if signed.Nonce() != tx.Nonce() || string(signed.Data()) != string(tx.Data()) {
+		t.Fatalf("signed transaction differs from the requested one")
+	}
+}
+
+func TestRemoteSignerErrorResponse(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	signer := newStubSigner(t, &stubSigner{address: crypto.PubkeyToAddress(key.PublicKey), key: key, fail: true})
+
+	tx := testTx()
+	_, err := signer.SignTx(context.Background(), tx, tx.ChainId())
+	if err == nil || !strings.Contains(err.Error(), "account is locked") {
+		t.Fatalf("SignTx error = %v, want the signer error", err)
+	}
+}
+
+func TestRemoteSignerAddressMismatch(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	signer := newStubSigner(t, &stubSigner{address: crypto.PubkeyToAddress(key.PublicKey), key: otherKey})
+
+	tx := testTx()
+	_, err := signer.SignTx(context.Background(), tx, tx.ChainId())
+	if err == nil || !strings.Contains(err.Error(), "is signed by "+crypto.PubkeyToAddress(otherKey.PublicKey).Hex()) {
+		t.Fatalf("SignTx error = %v, want a sender mismatch", err)
+	}
+}
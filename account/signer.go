@@ -0,0 +1,133 @@
+package account
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"lisk/utils"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer signs the transactions of one address. The private key, if there is
+// one, never leaves the signer.
+type Signer interface {
+	Address() common.Address
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// TypedDataSigner is implemented by signers that can sign EIP-712 messages,
+// e.g. Permit2 permits. The signature is 65 bytes r | s | v with v = 27 or 28.
+type TypedDataSigner interface {
+	SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error)
+}
+
+var errWatchOnly = errors.New("address has no private key")
+
+// KeySigner signs with a private key held in memory.
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+	line    string // line of the plain keys file, used to quarantine the key
+}
+
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{
+		key:     key,
+		address: crypto.PubkeyToAddress(key.PublicKey),
+	}
+}
+
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *KeySigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func (s *KeySigner) SignTypedData(ctx context.Context, data apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+
+	signature, err := crypto.Sign(hash, s.key)
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// AddressSigner is a watch-only account for modules that only read, e.g.
+// AirdropStatus with a list of addresses.
+type AddressSigner struct {
+	address common.Address
+}
+
+func (s *AddressSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *AddressSigner) SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, fmt.Errorf("%s: %w", s.address.Hex(), errWatchOnly)
+}
+
+// parseKeyLine turns a line of a keys file into a signer: a private key gives
+// a KeySigner, an address a watch-only AddressSigner.
+func parseKeyLine(line string) (Signer, error) {
+	line = strings.TrimSpace(line)
+
+	key, keyErr := utils.ParsePrivateKey(line)
+	if keyErr == nil {
+		signer := NewKeySigner(key)
+		signer.line = line
+		return signer, nil
+	}
+
+	if common.IsHexAddress(line) {
+		return &AddressSigner{address: common.HexToAddress(line)}, nil
+	}
+
+	return nil, keyErr
+}
+
+// SelectSigners picks the signers for the lines of a scheduler group. A line
+// is a private key or the address of one of the loaded signers.
+func SelectSigners(all []Signer, lines []string) ([]Signer, error) {
+	byAddress := make(map[common.Address]Signer, len(all))
+	for _, signer := range all {
+		byAddress[signer.Address()] = signer
+	}
+
+	selected := make([]Signer, 0, len(lines))
+	for i, line := range lines {
+		signer, err := parseKeyLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		if loaded, exists := byAddress[signer.Address()]; exists {
+			signer = loaded
+		}
+		selected = append(selected, signer)
+	}
+
+	return selected, nil
+}
+
+// Addresses returns the hex addresses of the signers.
+func Addresses(signers []Signer) []string {
+	addresses := make([]string, 0, len(signers))
+	for _, signer := range signers {
+		addresses = append(addresses, signer.Address().Hex())
+	}
+	return addresses
+}
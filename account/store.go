@@ -0,0 +1,133 @@
+package account
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"lisk/config"
+	"lisk/utils"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// StoreKeys adds private keys to a keystore directory or a vault. An existing
+// store is unlocked with its passphrase first, so every file keeps one
+// passphrase; keys that are already stored are skipped. Returns the number of
+// added keys.
+func StoreKeys(source config.KeySource, target, path string, keys []*ecdsa.PrivateKey) (int, error) {
+	switch target {
+	case KeySourceVault:
+		if path == "" {
+			path = utils.GetPath("vault")
+		}
+		return storeInVault(source, path, keys)
+	case KeySourceKeystore:
+		if path == "" {
+			path = utils.GetPath("keystore")
+		}
+		return storeInKeystore(source, path, keys)
+	default:
+		return 0, fmt.Errorf("unknown key store %q, expected keystore or vault", target)
+	}
+}
+
+func storeInVault(source config.KeySource, path string, keys []*ecdsa.PrivateKey) (int, error) {
+	var (
+		lines      []string
+		passphrase string
+		err        error
+	)
+
+	if _, statErr := os.Stat(path); statErr == nil {
+		if passphrase, err = readPassphrase(source, "Vault passphrase:"); err != nil {
+			return 0, err
+		}
+		if lines, err = ReadVault(path, passphrase); err != nil {
+			return 0, err
+		}
+	} else if errors.Is(statErr, os.ErrNotExist) {
+		if passphrase, err = ReadNewPassphrase(source); err != nil {
+			return 0, err
+		}
+	} else {
+		return 0, fmt.Errorf("failed to open vault: %w", statErr)
+	}
+
+	stored := make(map[common.Address]bool, len(lines))
+	for _, line := range lines {
+		if signer, err := parseKeyLine(line); err == nil {
+			stored[signer.Address()] = true
+		}
+	}
+
+	added := 0
+	for _, key := range keys {
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		if stored[address] {
+			continue
+		}
+		stored[address] = true
+		lines = append(lines, "0x"+hex.EncodeToString(crypto.FromECDSA(key)))
+		added++
+	}
+
+	if added == 0 {
+		return 0, nil
+	}
+	return added, WriteVault(path, passphrase, lines)
+}
+
+func storeInKeystore(source config.KeySource, dir string, keys []*ecdsa.PrivateKey) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("failed to read keystore directory: %w", err)
+	}
+
+	var existing string
+	for _, entry := range entries {
+		if !entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			existing = filepath.Join(dir, entry.Name())
+			break
+		}
+	}
+
+	var passphrase string
+	if existing != "" {
+		if passphrase, err = readPassphrase(source, "Keystore passphrase:"); err != nil {
+			return 0, err
+		}
+		data, err := os.ReadFile(existing)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read keystore %s: %w", existing, err)
+		}
+		if _, err := keystore.DecryptKey(data, passphrase); err != nil {
+			return 0, fmt.Errorf("passphrase does not unlock %s: %w", filepath.Base(existing), err)
+		}
+	} else if passphrase, err = ReadNewPassphrase(source); err != nil {
+		return 0, err
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return 0, fmt.Errorf("failed to create keystore directory: %w", err)
+	}
+
+	store := keystore.NewKeyStore(dir, keystore.StandardScryptN, keystore.StandardScryptP)
+	added := 0
+	for _, key := range keys {
+		if _, err := store.ImportECDSA(key, passphrase); err != nil {
+			if errors.Is(err, keystore.ErrAccountAlreadyExists) {
+				continue
+			}
+			return added, fmt.Errorf("failed to write keystore of %s: %w", crypto.PubkeyToAddress(key.PublicKey).Hex(), err)
+		}
+		added++
+	}
+
+	return added, nil
+}
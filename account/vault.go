@@ -0,0 +1,196 @@
+package account
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const vaultVersion = 1
+
+// Same cost as the "standard" scrypt parameters of go-ethereum keystores.
+const (
+	vaultScryptN = 1 << 18
+	vaultScryptR = 8
+	vaultScryptP = 1
+)
+
+// vaultFile is an encrypted list of private keys. The key of AES-256-GCM is
+// derived from the passphrase with scrypt.
+type vaultFile struct {
+	Version    int      `json:"version"`
+	KDF        vaultKDF `json:"kdf"`
+	Nonce      string   `json:"nonce"`
+	Ciphertext string   `json:"ciphertext"`
+	Addresses  []string `json:"addresses"` // not secret, authenticated together with the keys
+}
+
+type vaultKDF struct {
+	Name string `json:"name"`
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt string `json:"salt"`
+}
+
+type vaultContent struct {
+	Keys []string `json:"keys"`
+}
+
+// ReadVault decrypts the vault at path and returns its private keys.
+func ReadVault(path, passphrase string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault: %w", err)
+	}
+
+	var file vaultFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode vault: %w", err)
+	}
+	if file.Version != vaultVersion || file.KDF.Name != "scrypt" {
+		return nil, fmt.Errorf("unsupported vault version %d (%s)", file.Version, file.KDF.Name)
+	}
+
+	salt, err := hex.DecodeString(file.KDF.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault salt: %w", err)
+	}
+	nonce, err := hex.DecodeString(file.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault nonce: %w", err)
+	}
+	ciphertext, err := hex.DecodeString(file.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault ciphertext: %w", err)
+	}
+
+	gcm, err := vaultCipher(passphrase, salt, file.KDF.N, file.KDF.R, file.KDF.P)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid vault nonce length %d", len(nonce))
+	}
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, vaultAAD(file.Addresses))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt vault: wrong passphrase or damaged file")
+	}
+
+	var content vaultContent
+	if err := json.Unmarshal(plaintext, &content); err != nil {
+		return nil, fmt.Errorf("failed to decode vault content: %w", err)
+	}
+
+	return content.Keys, nil
+}
+
+// WriteVault encrypts the private keys with the passphrase and writes them to
+// path. Every key is checked before anything is written.
+func WriteVault(path, passphrase string, keys []string) error {
+	addresses := make([]string, 0, len(keys))
+	for i, key := range keys {
+		signer, err := parseKeyLine(key)
+		if err != nil {
+			return fmt.Errorf("key %d: %w", i+1, err)
+		}
+		if _, ok := signer.(*KeySigner); !ok {
+			return fmt.Errorf("key %d: %s is an address, not a private key", i+1, key)
+		}
+		addresses = append(addresses, signer.Address().Hex())
+	}
+
+	plaintext, err := json.Marshal(vaultContent{Keys: keys})
+	if err != nil {
+		return fmt.Errorf("failed to encode vault content: %w", err)
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := vaultCipher(passphrase, salt, vaultScryptN, vaultScryptR, vaultScryptP)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	file := vaultFile{
+		Version: vaultVersion,
+		KDF: vaultKDF{
+			Name: "scrypt",
+			N:    vaultScryptN,
+			R:    vaultScryptR,
+			P:    vaultScryptP,
+			Salt: hex.EncodeToString(salt),
+		},
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(gcm.Seal(nil, nonce, plaintext, vaultAAD(addresses))),
+		Addresses:  addresses,
+	}
+
+	data, err := json.MarshalIndent(file, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to encode vault: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write vault: %w", err)
+	}
+
+	return nil
+}
+
+// VaultAddresses returns the addresses stored next to the encrypted keys.
+func VaultAddresses(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault: %w", err)
+	}
+
+	var file vaultFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode vault: %w", err)
+	}
+	return file.Addresses, nil
+}
+
+func vaultAAD(addresses []string) []byte {
+	return []byte(strings.Join(addresses, ","))
+}
+
+func vaultCipher(passphrase string, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive vault key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,54 @@
+package account
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// WalletMeta is the optional label and proxy of one address, written by
+// `lisk wallet derive`. A proxy set here takes precedence over the line of
+// proxy.txt.
+type WalletMeta struct {
+	Label string `json:"label,omitempty"`
+	Proxy string `json:"proxy,omitempty"`
+}
+
+// LoadWallets reads the wallets file. A missing file is an empty list.
+func LoadWallets(path string) (map[common.Address]WalletMeta, error) {
+	wallets := make(map[common.Address]WalletMeta)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return wallets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallets file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &wallets); err != nil {
+		return nil, fmt.Errorf("failed to decode wallets file %s: %w", path, err)
+	}
+	return wallets, nil
+}
+
+// SaveWallets writes the wallets file atomically.
+func SaveWallets(path string, wallets map[common.Address]WalletMeta) error {
+	data, err := json.MarshalIndent(wallets, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to encode wallets file: %w", err)
+	}
+
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write wallets file: %w", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write wallets file: %w", err)
+	}
+	return nil
+}
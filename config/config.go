@@ -2,34 +2,46 @@ package config
 
 import (
 	"encoding/json"
+	"lisk/retry"
 	"os"
 )
 
 type Config struct {
-	Threads           int               `json:"threads"`
-	StartDate         string            `json:"start_date"`
-	ActionCounts      int               `json:"actions_count"`
-	MaxActionsTime    int               `json:"max_actions_time"`
-	IonicBorrow       string            `json:"ionic_borrow_amount"`
-	IonicSupply       string            `json:"ionic_supply_amount"`
-	OkuPercentUsage   int               `json:"oku_percen_usage"`
-	WrapMinAmount     string            `json:"min_amount_to_wrap"`
-	WrapMaxAmount     string            `json:"max_amount_to_wrap"`
-	SwapUSDTMinAmount string            `json:"min_usdc_amount_to_swap"`
-	SwapUSDTMaxAmount string            `json:"max_usdc_amount_to_swap"`
-	SwapEthMaxAmount  string            `json:"max_eth_amount_to_swap"`
-	SwapEthMinAmount  string            `json:"min_eth_amount_to_swap"`
-	MinUSDTForSwap    string            `json:"min_usdt_amount_to_swap"`
-	AttentionGwei     string            `json:"attention_gwei"`
-	AttentionTime     int               `json:"attention_time_cycle"`
-	MaxAttentionTime  int               `json:"max_attention_time"`
-	StateFile         string            `json:"state_file"`
-	RPC               map[string]string `json:"rpc"`
-	ABIs              map[string]string `json:"abis"`
-	TokenAddresses    map[string]string `json:"token_addresses"`
-	OkuAddresses      map[string]string `json:"oku_addresses"`
-	IonicAddresses    map[string]string `json:"ionic_addresses"`
-	Endpoints         map[string]string `json:"enpoints"`
+	Threads           int                     `json:"threads"`
+	StartDate         string                  `json:"start_date"`
+	ActionCounts      int                     `json:"actions_count"`
+	MaxActionsTime    int                     `json:"max_actions_time"`
+	IonicBorrow       string                  `json:"ionic_borrow_amount"`
+	IonicSupply       string                  `json:"ionic_supply_amount"`
+	OkuPercentUsage   int                     `json:"oku_percen_usage"`
+	WrapMinAmount     string                  `json:"min_amount_to_wrap"`
+	WrapMaxAmount     string                  `json:"max_amount_to_wrap"`
+	SwapUSDTMinAmount string                  `json:"min_usdc_amount_to_swap"`
+	SwapUSDTMaxAmount string                  `json:"max_usdc_amount_to_swap"`
+	SwapEthMaxAmount  string                  `json:"max_eth_amount_to_swap"`
+	SwapEthMinAmount  string                  `json:"min_eth_amount_to_swap"`
+	MinUSDTForSwap    string                  `json:"min_usdt_amount_to_swap"`
+	AttentionGwei     string                  `json:"attention_gwei"`
+	AttentionTxCost   string                  `json:"attention_tx_cost"`
+	GasBudgetDaily    string                  `json:"gas_budget_daily"`
+	GasBudgetRun      string                  `json:"gas_budget_run"`
+	AttentionTime     int                     `json:"attention_time_cycle"`
+	MaxAttentionTime  int                     `json:"max_attention_time"`
+	StateFile         string                  `json:"state_file"`
+	DryRun            bool                    `json:"dry_run"`
+	TxReplaceAfter    int                     `json:"tx_replace_after"`
+	TxFeeBumpPercent  int                     `json:"tx_fee_bump_percent"`
+	TxMaxFeeBumps     int                     `json:"tx_max_fee_bumps"`
+	SlippageBps       *int                    `json:"slippage_bps"`         // nil - default, 0 is a valid value
+	MaxPriceImpactBps *int                    `json:"max_price_impact_bps"` // nil - default, 0 is a valid value
+	RetryPolicies     map[string]retry.Policy `json:"retry_policies"`
+	KeySource         KeySource               `json:"key_source"`
+	RPC               map[string]RPCEndpoints `json:"rpc"`
+	ABIs              map[string]string       `json:"abis"`
+	TokenAddresses    map[string]string       `json:"token_addresses"`
+	OkuAddresses      map[string]string       `json:"oku_addresses"`
+	IonicAddresses    map[string]string       `json:"ionic_addresses"`
+	Endpoints         map[string]string       `json:"enpoints"`
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -0,0 +1,24 @@
+package config
+
+// KeySource tells where the account keys are loaded from:
+//   - "file": plain text file with one private key per line (default)
+//   - "keystore": go-ethereum V3 keystore JSON files, Path is a file or a directory
+//   - "vault": one encrypted file with many keys, see `lisk vault create`
+//   - "remote": external signer at URL with eth_accounts and eth_signTransaction
+//   - "mnemonic": IndexCount keys of a BIP-39 mnemonic from IndexFrom along HDPath
+//
+// Encrypted sources are unlocked with the passphrase from the PassphraseEnv
+// environment variable, or with a prompt when it is not set. The mnemonic is
+// read from MnemonicEnv or asked for, MnemonicPassphraseEnv holds its optional
+// BIP-39 passphrase.
+type KeySource struct {
+	Type                  string `json:"type"`
+	Path                  string `json:"path"`
+	PassphraseEnv         string `json:"passphrase_env"`
+	URL                   string `json:"url"`
+	MnemonicEnv           string `json:"mnemonic_env"`
+	MnemonicPassphraseEnv string `json:"mnemonic_passphrase_env"`
+	HDPath                string `json:"hd_path"`
+	IndexFrom             int    `json:"index_from"`
+	IndexCount            int    `json:"index_count"`
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RPCEndpoint is one node of a chain. Endpoints with a higher weight get a
+// proportionally bigger share of requests.
+type RPCEndpoint struct {
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// RPCEndpoints accepts a single URL, a list of URLs or a list of
+// {"url", "weight"} objects, so old configs keep working.
+type RPCEndpoints []RPCEndpoint
+
+func (e *RPCEndpoints) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*e = RPCEndpoints{{URL: single, Weight: 1}}
+		return nil
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("rpc: expected a URL, a list of URLs or a list of {url, weight} objects")
+	}
+
+	endpoints := make(RPCEndpoints, 0, len(list))
+	for _, raw := range list {
+		var endpoint RPCEndpoint
+		if err := json.Unmarshal(raw, &endpoint.URL); err != nil {
+			if err := json.Unmarshal(raw, &endpoint); err != nil {
+				return fmt.Errorf("rpc: invalid endpoint %s", raw)
+			}
+		}
+
+		if endpoint.URL == "" {
+			return fmt.Errorf("rpc: endpoint without url: %s", raw)
+		}
+		if endpoint.Weight < 0 {
+			return fmt.Errorf("rpc: negative weight for %s", endpoint.URL)
+		}
+		if endpoint.Weight == 0 {
+			endpoint.Weight = 1
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	*e = endpoints
+	return nil
+}
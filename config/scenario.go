@@ -0,0 +1,50 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// RouteStep is one module of a scenario. Actions overrides the default action
+// count of the module, Probability (0..1) makes the step optional.
+type RouteStep struct {
+	Module      string   `json:"module"`
+	Actions     int      `json:"actions,omitempty"`
+	Probability *float64 `json:"probability,omitempty"`
+}
+
+type Scenario struct {
+	Name  string      `json:"name"`
+	Steps []RouteStep `json:"steps"`
+}
+
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenario Scenario
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return nil, err
+	}
+
+	if len(scenario.Steps) == 0 {
+		return nil, fmt.Errorf("scenario %s has no steps", path)
+	}
+
+	for i, step := range scenario.Steps {
+		if step.Module == "" {
+			return nil, fmt.Errorf("scenario %s: step %d has no module", path, i+1)
+		}
+		if step.Actions < 0 {
+			return nil, fmt.Errorf("scenario %s: step %d has negative actions", path, i+1)
+		}
+		if step.Probability != nil && (*step.Probability < 0 || *step.Probability > 1) {
+			return nil, fmt.Errorf("scenario %s: step %d probability must be between 0 and 1", path, i+1)
+		}
+	}
+
+	return &scenario, nil
+}
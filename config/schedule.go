@@ -0,0 +1,66 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Job is one recurring run of the daemon. Exactly one of Module or Scenario
+// must be set. Group refers to a key file from Schedule.Groups, an empty group
+// means the default privateKeys.txt.
+type Job struct {
+	Name          string `json:"name"`
+	Module        string `json:"module,omitempty"`
+	Scenario      string `json:"scenario,omitempty"`
+	Cron          string `json:"cron"`
+	WindowMinutes int    `json:"window_minutes"`
+	Group         string `json:"group,omitempty"`
+}
+
+type Schedule struct {
+	StateFile string            `json:"state_file"`
+	Groups    map[string]string `json:"groups"`
+	Jobs      []Job             `json:"jobs"`
+}
+
+func LoadSchedule(path string) (*Schedule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule Schedule
+	if err := json.Unmarshal(data, &schedule); err != nil {
+		return nil, err
+	}
+
+	if len(schedule.Jobs) == 0 {
+		return nil, fmt.Errorf("schedule %s has no jobs", path)
+	}
+
+	names := make(map[string]bool)
+	for i, job := range schedule.Jobs {
+		if job.Name == "" {
+			return nil, fmt.Errorf("schedule %s: job %d has no name", path, i+1)
+		}
+		if names[job.Name] {
+			return nil, fmt.Errorf("schedule %s: duplicate job name %s", path, job.Name)
+		}
+		names[job.Name] = true
+
+		if (job.Module == "") == (job.Scenario == "") {
+			return nil, fmt.Errorf("schedule %s: job %s must have either module or scenario", path, job.Name)
+		}
+		if job.Group != "" {
+			if _, exists := schedule.Groups[job.Group]; !exists {
+				return nil, fmt.Errorf("schedule %s: job %s refers to unknown group %s", path, job.Name, job.Group)
+			}
+		}
+		if job.WindowMinutes < 0 {
+			return nil, fmt.Errorf("schedule %s: job %s has negative window", path, job.Name)
+		}
+	}
+
+	return &schedule, nil
+}
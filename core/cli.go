@@ -0,0 +1,430 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"lisk/account"
+	"lisk/config"
+	"lisk/core/process"
+	"lisk/core/scheduler"
+	"lisk/globals"
+	"lisk/logger"
+	"lisk/utils"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const usage = `Usage: lisk [command] [flags]
+
+Without a command the interactive menu is started.
+
+Commands:
+  run       --module <name> | --scenario <path> [--resume=yes|no] [--dry-run] [--config <path>]
+  daemon    [--schedule <path>] [--config <path>]
+  balances  [--config <path>]
+  state     show|clear [--config <path>]
+  vault     create [--keys <path>] [--out <path>] | list [--vault <path>]
+  wallet    derive --count <n> [--from <i>] [--path <hd path>] [--to keystore|vault] [--out <path>] [--label <name>] [--proxies <path>] [--new] | list
+  modules   list available modules`
+
+func runCommand(ctx context.Context, args []string) error {
+	switch args[0] {
+	case "run":
+		return runModuleCommand(ctx, args[1:])
+	case "daemon":
+		return runDaemonCommand(ctx, args[1:])
+	case "balances":
+		return runBalancesCommand(ctx, args[1:])
+	case "state":
+		return runStateCommand(args[1:])
+	case "vault":
+		return runVaultCommand(args[1:])
+	case "wallet":
+		return runWalletCommand(args[1:])
+	case "modules":
+		fmt.Println(strings.Join(process.ModuleNames(), "\n"))
+		return nil
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return nil
+	default:
+		fmt.Println(usage)
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func runModuleCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	module := fs.String("module", "", "module to run, see `lisk modules`")
+	scenarioPath := fs.String("scenario", "", "scenario file with a route of modules, e.g. "+utils.GetPath("scenario"))
+	resume := fs.String("resume", "yes", "continue a saved run from the state file (yes|no)")
+	dryRun := fs.Bool("dry-run", false, "simulate transactions instead of broadcasting them")
+	configPath := fs.String("config", utils.GetPath("config"), "path to config.json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dryRun {
+		globals.DryRun = true
+	}
+
+	if *module != "" && !process.HasModule(*module) {
+		return fmt.Errorf("unknown module %q, available: %s", *module, strings.Join(process.ModuleNames(), ", "))
+	}
+
+	if *module != "" && *scenarioPath != "" {
+		return fmt.Errorf("--module and --scenario cannot be used together")
+	}
+
+	var scenario *config.Scenario
+	if *scenarioPath != "" {
+		var err error
+		if scenario, err = config.LoadScenario(*scenarioPath); err != nil {
+			return fmt.Errorf("failed to load scenario: %w", err)
+		}
+		if err := process.ValidateRoute(scenario.Steps); err != nil {
+			return err
+		}
+	}
+	selected := *module != "" || scenario != nil
+
+	if err := utils.CheckVersion(); err != nil {
+		logger.GlobalLogger.Warn(err)
+	}
+
+	a, err := newApp(*configPath, false)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	hasSavedState, err := a.memory.IsStateFileNotEmpty()
+	if err != nil {
+		return fmt.Errorf("failed to check state file: %w", err)
+	}
+
+	switch *resume {
+	case "yes":
+		if !hasSavedState && !selected {
+			return fmt.Errorf("no saved state to resume, pass --module or --scenario")
+		}
+	case "no":
+		if hasSavedState {
+			if err := a.memory.ClearAllStates(); err != nil {
+				return fmt.Errorf("failed to clear state file: %w", err)
+			}
+		}
+		if !selected {
+			return fmt.Errorf("--module or --scenario is required with --resume=no")
+		}
+	default:
+		return fmt.Errorf("invalid --resume value %q, use yes or no", *resume)
+	}
+
+	if scenario != nil {
+		return a.runScenario(ctx, scenario)
+	}
+	return a.run(ctx, *module)
+}
+
+func runDaemonCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("daemon", flag.ContinueOnError)
+	schedulePath := fs.String("schedule", utils.GetPath("schedule"), "path to schedule.json")
+	configPath := fs.String("config", utils.GetPath("config"), "path to config.json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	schedule, err := config.LoadSchedule(*schedulePath)
+	if err != nil {
+		return fmt.Errorf("failed to load schedule: %w", err)
+	}
+
+	for _, job := range schedule.Jobs {
+		if job.Module != "" && !process.HasModule(job.Module) {
+			return fmt.Errorf("job %s: unknown module %q", job.Name, job.Module)
+		}
+	}
+
+	a, err := newApp(*configPath, false)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	sched, err := scheduler.New(schedule, account.Addresses(a.signers), a.runJob)
+	if err != nil {
+		return err
+	}
+
+	logger.GlobalLogger.Infof("Daemon started with %d jobs", len(schedule.Jobs))
+	if err := sched.Start(ctx); err != nil && ctx.Err() == nil {
+		return err
+	}
+	logger.GlobalLogger.Infof("Daemon stopped")
+	return nil
+}
+
+func runBalancesCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("balances", flag.ContinueOnError)
+	configPath := fs.String("config", utils.GetPath("config"), "path to config.json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	a, err := newApp(*configPath, true)
+	if err != nil {
+		return err
+	}
+	defer a.close()
+
+	return a.run(ctx, "BalanceCheck")
+}
+
+func runStateCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("state: expected show or clear")
+	}
+
+	fs := flag.NewFlagSet("state", flag.ContinueOnError)
+	configPath := fs.String("config", utils.GetPath("config"), "path to config.json")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	memory, err := process.NewMemory(cfg.StateFile)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "show":
+		states := memory.States()
+		if len(states) == 0 {
+			fmt.Println("State file is empty.")
+			return nil
+		}
+		for _, state := range states {
+			fmt.Printf("%s\tstep %d/%d\t%s\t%d\n", state.AccountAddress, state.Step+1, max(len(state.Route), 1), state.Module, state.LastActionIndex)
+		}
+		return nil
+	case "clear":
+		if err := memory.ClearAllStates(); err != nil {
+			return fmt.Errorf("failed to clear state file: %w", err)
+		}
+		logger.GlobalLogger.Infof("State file %s cleared", cfg.StateFile)
+		return nil
+	default:
+		return fmt.Errorf("state: unknown action %q, expected show or clear", args[0])
+	}
+}
+
+// runVaultCommand creates an encrypted vault from the plain keys file or lists
+// the addresses of a vault. The passphrase is taken from the environment
+// variable of key_source or asked for.
+func runVaultCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("vault: expected create or list")
+	}
+
+	fs := flag.NewFlagSet("vault", flag.ContinueOnError)
+	keysPath := fs.String("keys", utils.GetPath("privateKeys"), "plain text file with one private key per line")
+	outPath := fs.String("out", utils.GetPath("vault"), "vault file to create")
+	vaultPath := fs.String("vault", utils.GetPath("vault"), "vault file to list")
+	configPath := fs.String("config", utils.GetPath("config"), "path to config.json")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "create":
+		var source config.KeySource
+		if cfg, err := config.LoadConfig(*configPath); err == nil {
+			source = cfg.KeySource
+		}
+
+		keys, err := utils.FileReader(*keysPath)
+		if err != nil {
+			return fmt.Errorf("failed to read keys: %w", err)
+		}
+		lines := make([]string, 0, len(keys))
+		for _, key := range keys {
+			if key = strings.TrimSpace(key); key != "" {
+				lines = append(lines, key)
+			}
+		}
+
+		passphrase, err := account.ReadNewPassphrase(source)
+		if err != nil {
+			return err
+		}
+		if err := account.WriteVault(*outPath, passphrase, lines); err != nil {
+			return err
+		}
+
+		logger.GlobalLogger.Infof("Vault %s created with %d keys. Set key_source to {\"type\": \"vault\"} and delete %s", *outPath, len(lines), *keysPath)
+		return nil
+	case "list":
+		addresses, err := account.VaultAddresses(*vaultPath)
+		if err != nil {
+			return err
+		}
+		fmt.Println(strings.Join(addresses, "\n"))
+		return nil
+	default:
+		return fmt.Errorf("vault: unknown action %q, expected create or list", args[0])
+	}
+}
+
+// runWalletCommand derives accounts from a BIP-39 mnemonic into the keystore
+// or the vault, or lists the labels and proxies of the wallets file. The
+// mnemonic is taken from the environment variable of key_source or asked for.
+func runWalletCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("wallet: expected derive or list")
+	}
+
+	fs := flag.NewFlagSet("wallet", flag.ContinueOnError)
+	count := fs.Int("count", 0, "number of accounts to derive")
+	from := fs.Int("from", 0, "first account index")
+	hdPath := fs.String("path", "", "BIP-44 derivation path, {i} is the account index (default: hd_path of key_source or "+account.DefaultHDPath+")")
+	to := fs.String("to", "", "store for the keys: keystore or vault (default: type of key_source, vault for other types)")
+	outPath := fs.String("out", "", "keystore directory or vault file (default: path of key_source or "+utils.GetPath("vault")+")")
+	label := fs.String("label", "", "label of the accounts, the index is appended when several accounts are derived")
+	proxiesPath := fs.String("proxies", "", "file with one proxy per derived account")
+	newMnemonic := fs.Bool("new", false, "generate a new 24 word mnemonic instead of reading one")
+	configPath := fs.String("config", utils.GetPath("config"), "path to config.json")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	var source config.KeySource
+	if cfg, err := config.LoadConfig(*configPath); err == nil {
+		source = cfg.KeySource
+	}
+
+	switch args[0] {
+	case "derive":
+		if *count <= 0 {
+			return fmt.Errorf("wallet derive: --count is required")
+		}
+
+		var proxies []string
+		if *proxiesPath != "" {
+			lines, err := utils.FileReader(*proxiesPath)
+			if err != nil {
+				return fmt.Errorf("failed to read proxies: %w", err)
+			}
+			for _, line := range lines {
+				if line = strings.TrimSpace(line); line != "" {
+					proxies = append(proxies, line)
+				}
+			}
+			if len(proxies) < *count {
+				logger.GlobalLogger.Warnf("%d proxies for %d accounts, the rest use proxy.txt", len(proxies), *count)
+			}
+		}
+
+		var (
+			mnemonic string
+			err      error
+		)
+		if *newMnemonic {
+			if mnemonic, err = account.NewMnemonic(); err != nil {
+				return err
+			}
+			fmt.Printf("New mnemonic, write it down and keep it offline:\n\n%s\n\n", mnemonic)
+		} else if mnemonic, err = account.ReadMnemonic(source); err != nil {
+			return err
+		}
+
+		path := *hdPath
+		if path == "" {
+			path = source.HDPath
+		}
+		keys, err := account.DeriveKeys(mnemonic, account.MnemonicPassphrase(source), path, *from, *count)
+		if err != nil {
+			return err
+		}
+
+		target, out := *to, *outPath
+		if target == "" {
+			target = account.KeySourceVault
+			if source.Type == account.KeySourceKeystore {
+				target = account.KeySourceKeystore
+			}
+		}
+		if out == "" && source.Type == target {
+			out = source.Path
+		}
+
+		added, err := account.StoreKeys(source, target, out, keys)
+		if err != nil {
+			return err
+		}
+
+		wallets, err := account.LoadWallets(utils.GetPath("wallets"))
+		if err != nil {
+			return err
+		}
+		changed := false
+		for i, key := range keys {
+			address := crypto.PubkeyToAddress(key.PublicKey)
+			meta := wallets[address]
+			if *label != "" {
+				meta.Label = *label
+				if *count > 1 {
+					meta.Label = fmt.Sprintf("%s-%d", *label, *from+i)
+				}
+			}
+			if i < len(proxies) {
+				meta.Proxy = proxies[i]
+			}
+			if meta != wallets[address] {
+				wallets[address] = meta
+				changed = true
+			}
+			fmt.Printf("%d\t%s\t%s\n", *from+i, address.Hex(), meta.Label)
+		}
+		if changed {
+			if err := account.SaveWallets(utils.GetPath("wallets"), wallets); err != nil {
+				return err
+			}
+		}
+
+		logger.GlobalLogger.Infof("Derived %d account(s), %d new added to the %s. Set key_source to {\"type\": \"%s\"} to use them", len(keys), added, target, target)
+		return nil
+	case "list":
+		wallets, err := account.LoadWallets(utils.GetPath("wallets"))
+		if err != nil {
+			return err
+		}
+		if len(wallets) == 0 {
+			fmt.Println("Wallets file is empty.")
+			return nil
+		}
+
+		addresses := make([]common.Address, 0, len(wallets))
+		for address := range wallets {
+			addresses = append(addresses, address)
+		}
+		sort.Slice(addresses, func(i, j int) bool {
+			return wallets[addresses[i]].Label < wallets[addresses[j]].Label
+		})
+		for _, address := range addresses {
+			fmt.Printf("%s\t%s\t%s\n", address.Hex(), wallets[address].Label, wallets[address].Proxy)
+		}
+		return nil
+	default:
+		return fmt.Errorf("wallet: unknown action %q, expected derive or list", args[0])
+	}
+}
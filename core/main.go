@@ -1,69 +1,150 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"lisk/account"
+	"lisk/config"
 	"lisk/core/process"
 	"lisk/ethClient"
 	"lisk/globals"
 	"lisk/logger"
 	"lisk/modules"
 	"lisk/utils"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 )
 
+type app struct {
+	cfg     *config.Config
+	signers []account.Signer
+	proxies []string
+	wallets map[common.Address]account.WalletMeta
+	clients map[string]*ethClient.Client
+	abis    map[string]*abi.ABI
+	memory  *process.Memory
+	mods    map[string]modules.ModulesFasad
+}
+
 func main() {
 	_ = utils.SetConsoleTitle(globals.ConsoleTitle)
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		// A second signal kills the process immediately.
+		stop()
+		logger.GlobalLogger.Warnf("Shutdown requested. Finishing in-flight transactions and saving progress...")
+	}()
+
+	if len(os.Args) > 1 {
+		err := runCommand(ctx, os.Args[1:])
+		stop()
+		// -h prints the usage of the command, it is not a failure.
+		if err != nil && !errors.Is(err, flag.ErrHelp) {
+			logger.GlobalLogger.Error(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	runInteractive(ctx)
+	stop()
+}
+
+func runInteractive(ctx context.Context) {
 	utils.PrintStartMessage()
 	utils.GasPricesPrint()
 	if err := utils.CheckVersion(); err != nil {
 		logger.GlobalLogger.Warn(err)
 	}
 
-	privateKeys, err := utils.GetPrivateKeys()
+	a, err := newApp(utils.GetPath("config"), false)
 	if err != nil {
 		logger.GlobalLogger.Error(err)
 		return
 	}
+	defer a.close()
 
-	cfg, err := utils.GetConfig()
+	selectModule, err := determineModuleForRun(a.memory)
 	if err != nil {
 		logger.GlobalLogger.Error(err)
 		return
 	}
 
+	if err := a.run(ctx, selectModule); err != nil {
+		logger.GlobalLogger.Error(err)
+		return
+	}
+	utils.PrintStartMessage()
+}
+
+// newApp loads the config and initialises everything a run needs. With
+// ephemeralState (or in dry-run mode) the state file is ignored, so read-only commands never
+// touch the progress of an interrupted run.
+func newApp(configPath string, ephemeralState bool) (*app, error) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	signers, err := account.LoadSigners(cfg.KeySource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load keys: %w", err)
+	}
+
+	wallets, err := account.LoadWallets(utils.GetPath("wallets"))
+	if err != nil {
+		return nil, err
+	}
+
 	process.InitGlobals(cfg)
 
 	clients, err := ethClient.EthClientFactory(cfg.RPC)
 	if err != nil {
-		logger.GlobalLogger.Error(err)
-		return
+		return nil, err
 	}
-	defer ethClient.CloseAllClients(clients)
 
 	abis, err := utils.ReadAbis(cfg.ABIs)
 	if err != nil {
-		logger.GlobalLogger.Error(err)
-		return
+		ethClient.CloseAllClients(clients)
+		return nil, err
 	}
 
-	memory, err := process.NewMemory(cfg.StateFile)
-	if err != nil {
-		logger.GlobalLogger.Warn(err)
-		return
+	ethClient.RegisterABIs(abis)
+
+	statePath := cfg.StateFile
+	if ephemeralState || globals.DryRun {
+		statePath = ""
 	}
 
-	selectModule, err := determineModuleForRun(memory)
+	memory, err := process.NewMemory(statePath)
 	if err != nil {
-		logger.GlobalLogger.Error(err)
-		return
+		ethClient.CloseAllClients(clients)
+		return nil, err
 	}
 
 	mods, err := modules.ModulesInit(cfg, abis, clients)
 	if err != nil {
-		logger.GlobalLogger.Error(err)
-		return
+		ethClient.CloseAllClients(clients)
+		return nil, err
+	}
+
+	if !globals.DryRun {
+		journal, err := ethClient.OpenJournal(utils.GetPath("journal"))
+		if err != nil {
+			ethClient.CloseAllClients(clients)
+			return nil, err
+		}
+		ethClient.TxJournal = journal
 	}
 
 	proxies, err := utils.GetProxies()
@@ -71,20 +152,108 @@ func main() {
 		logger.GlobalLogger.Warn(err)
 	}
 
-	accs, err := account.AccsFactory(privateKeys, proxies, cfg, selectModule)
+	return &app{
+		cfg:     cfg,
+		signers: signers,
+		proxies: proxies,
+		wallets: wallets,
+		clients: clients,
+		abis:    abis,
+		memory:  memory,
+		mods:    mods,
+	}, nil
+}
+
+func (a *app) close() {
+	if err := a.memory.Flush(); err != nil {
+		logger.GlobalLogger.Warnf("Failed to flush state file: %v", err)
+	}
+	if err := ethClient.TxJournal.Close(); err != nil {
+		logger.GlobalLogger.Warnf("Failed to close transaction journal: %v", err)
+	}
+	ethClient.CloseAllClients(a.clients)
+}
+
+func (a *app) run(ctx context.Context, selectModule string) error {
+	var route []config.RouteStep
+	if selectModule != "" {
+		route = []config.RouteStep{{Module: selectModule}}
+	}
+
+	return a.runRoute(ctx, selectModule, route)
+}
+
+func (a *app) runScenario(ctx context.Context, scenario *config.Scenario) error {
+	logger.GlobalLogger.Infof("Scenario '%s': %d steps", scenario.Name, len(scenario.Steps))
+	return a.runRoute(ctx, "", scenario.Steps)
+}
+
+func (a *app) runRoute(ctx context.Context, selectModule string, route []config.RouteStep) error {
+	accs, err := account.AccsFactory(a.signers, a.proxies, a.wallets, a.cfg, selectModule)
 	if err != nil {
-		logger.GlobalLogger.Error(err)
-		return
+		return err
 	}
 	logger.GlobalLogger.Infof("All settings are initialised! Sleep 5 seconds...")
-	time.Sleep(time.Second * 5)
+	if err := utils.SleepContext(ctx, time.Second*5); err != nil {
+		return err
+	}
 
-	if err := process.ProcessAccounts(accs, selectModule, mods, clients, memory); err != nil {
-		logger.GlobalLogger.Error(err)
-		return
+	err = process.ProcessRoute(ctx, accs, route, a.mods, a.clients, a.memory)
+	if globals.DryRun {
+		ethClient.PrintDryRunSummary()
+	}
+	if err != nil {
+		return err
 	}
 	logger.GlobalLogger.Infof("Account processed successfully")
-	utils.PrintStartMessage()
+	return nil
+}
+
+// runJob executes one scheduler job. Every job keeps its own state file, so an
+// interrupted job resumes on its next start without touching manual runs.
+func (a *app) runJob(ctx context.Context, job config.Job, keys []string) ([]string, error) {
+	route := []config.RouteStep{{Module: job.Module}}
+	if job.Scenario != "" {
+		scenario, err := config.LoadScenario(job.Scenario)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load scenario: %w", err)
+		}
+		route = scenario.Steps
+	}
+
+	memory, err := process.NewMemory(strings.TrimSuffix(a.cfg.StateFile, ".json") + "_" + job.Name + ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	signers, err := account.SelectSigners(a.signers, keys)
+	if err != nil {
+		return nil, fmt.Errorf("job %s: %w", job.Name, err)
+	}
+
+	accs, err := account.AccsFactory(signers, a.proxies, a.wallets, a.cfg, job.Module)
+	if err != nil {
+		return nil, err
+	}
+
+	err = process.ProcessRoute(ctx, accs, route, a.mods, a.clients, memory)
+
+	var runErrs *process.RunErrors
+	if err != nil && !errors.As(err, &runErrs) {
+		return nil, err
+	}
+
+	completed := make([]string, 0, len(accs))
+	for _, acc := range accs {
+		if runErrs != nil {
+			if _, failed := runErrs.Failed[acc.Address.Hex()]; failed {
+				continue
+			}
+		}
+		completed = append(completed, acc.Address.Hex())
+	}
+
+	return completed, err
 }
 
 func determineModuleForRun(memory *process.Memory) (string, error) {
@@ -1,6 +1,7 @@
 package process
 
 import (
+	"context"
 	"fmt"
 	"lisk/account"
 	"lisk/ethClient"
@@ -8,10 +9,11 @@ import (
 	"lisk/logger"
 	"math/big"
 	"math/rand"
+	"sort"
 	"time"
 )
 
-var actionGenerators = map[string]func(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error){
+var actionGenerators = map[string]func(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error){
 	"AirdropStatus":      generateAirdropChecker,
 	"Oku":                generateSwap,
 	"IonicWithdrawAll":   generateIonicWithdraw,
@@ -26,6 +28,21 @@ var actionGenerators = map[string]func(acc *account.Account, clients map[string]
 	"Wrap_Unwrap":        generateWrapers,
 }
 
+// ModuleNames returns the names of all modules that can be selected for a run.
+func ModuleNames() []string {
+	names := make([]string, 0, len(actionGenerators))
+	for name := range actionGenerators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func HasModule(name string) bool {
+	_, exists := actionGenerators[name]
+	return exists
+}
+
 func generateTimeWindow(totalTime, actionCount int) []time.Duration {
 	if actionCount <= 0 {
 		return nil
@@ -59,28 +76,28 @@ func generateTimeWindow(totalTime, actionCount int) []time.Duration {
 	return intervals
 }
 
-func generateNextAction(acc *account.Account, selectedModule string, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generateNextAction(ctx context.Context, acc *account.Account, selectedModule string, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	generator, exists := actionGenerators[selectedModule]
 	if !exists {
 		return ActionProcess{TypeAction: globals.Unknown}, fmt.Errorf("no action generator for module '%s'", selectedModule)
 	}
 
-	return generator(acc, clients)
+	return generator(ctx, acc, clients)
 }
 
-func generateChecker(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generateChecker(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	return packActionProcessStruct(globals.Checker, "Portal", big.NewInt(0), globals.NULL, globals.NULL), nil
 }
 
-func generateDailyCheck(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generateDailyCheck(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	return packActionProcessStruct(globals.DailyCheck, "Portal", big.NewInt(0), globals.NULL, globals.NULL), nil
 }
 
-func generateMainTasks(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generateMainTasks(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	return packActionProcessStruct(globals.MainTasks, "Portal", big.NewInt(0), globals.NULL, globals.NULL), nil
 }
 
-func generateSwap(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generateSwap(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	for attemps := 0; attemps < 5; attemps++ {
 		if err := validateSwapHistory(acc.LastSwaps); err != nil {
 			return ActionProcess{TypeAction: globals.Unknown}, err
@@ -89,21 +106,24 @@ func generateSwap(acc *account.Account, clients map[string]*ethClient.Client) (A
 		tokenFrom := selectTokenFrom(acc)
 		tokenTo := selectDifferentToken(tokenFrom)
 
-		ethBal, err := validateNativeBalance(acc.Address, clients["lisk"])
+		balances, err := accountBalances(ctx, acc.Address, clients["lisk"], globals.Tokens...)
 		if err != nil {
+			return ActionProcess{TypeAction: globals.Unknown}, fmt.Errorf("failed to read balances: %w", err)
+		}
+
+		ethBal := balances[globals.WETH]
+		if err := checkNativeBalance(ethBal); err != nil {
 			return ActionProcess{TypeAction: globals.Unknown}, err
 		}
 
 		forced := false
 		if ethBal.Cmp(globals.MinBalances[globals.WETH]) < 0 {
 			tokenTo = globals.WETH
-			for attemps := 0; attemps < 5; attemps++ {
-				tokenFrom = selectDifferentToken(tokenTo)
-			}
+			tokenFrom = selectSwapSource(acc, tokenTo)
 			forced = true
 		}
 
-		amount, err := canDoActionByBalance(tokenFrom, acc, clients["lisk"])
+		amount, err := canDoActionByBalance(tokenFrom, acc, balances)
 		if err != nil {
 			return ActionProcess{TypeAction: globals.Unknown}, err
 		}
@@ -115,7 +135,7 @@ func generateSwap(acc *account.Account, clients map[string]*ethClient.Client) (A
 	return ActionProcess{TypeAction: globals.Unknown}, fmt.Errorf("failed to generate swap after 5 attempts")
 }
 
-func generate15Borrow(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generate15Borrow(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	if acc.LiquidityState.ActionCount == 0 {
 		acc.LiquidityState.ActionCount++
 		return packActionProcessStruct(globals.EnterMarket, "Ionic", big.NewInt(0), globals.USDT, globals.NULL), nil
@@ -124,15 +144,15 @@ func generate15Borrow(acc *account.Account, clients map[string]*ethClient.Client
 	return packActionProcessStruct(globals.Borrow, "Ionic", globals.IonicBorrow, globals.LISK, globals.NULL), nil
 }
 
-func generateIonic71Supply(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generateIonic71Supply(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	return packActionProcessStruct(globals.Supply, "Ionic", globals.IonicSupply, globals.USDT, globals.NULL), nil
 }
 
-func generateIonicRepay(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generateIonicRepay(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	return packActionProcessStruct(globals.Repay, "Ionic", globals.MaxUint256, globals.LISK, globals.NULL), nil
 }
 
-func generateWrapers(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generateWrapers(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	amount := getRandomValue(acc.WrapRange.Min, acc.WrapRange.Max)
 
 	switch acc.WrapHistory.LastAction {
@@ -146,7 +166,7 @@ func generateWrapers(acc *account.Account, clients map[string]*ethClient.Client)
 	}
 }
 
-func generateIonicWithdraw(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generateIonicWithdraw(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	switch acc.LiquidityState.LastAction {
 	case globals.ExitMarket:
 		updateLiquidityState(acc, globals.Redeem)
@@ -157,20 +177,20 @@ func generateIonicWithdraw(acc *account.Account, clients map[string]*ethClient.C
 	}
 }
 
-func generateBridgeToLisk(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
-	chain, balance, err := getMaxBalance(acc, clients)
+func generateBridgeToLisk(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+	chain, balance, err := getMaxBalance(ctx, acc, clients)
 	if err != nil {
-		return ActionProcess{TypeAction: globals.Unknown}, fmt.Errorf("failed get max balance in all chains: %v", err)
+		return ActionProcess{TypeAction: globals.Unknown}, fmt.Errorf("failed get max balance in all chains: %w", err)
 	}
 
 	if chain == "" {
-		return ActionProcess{TypeAction: globals.Unknown}, fmt.Errorf("no balance in other networks")
+		return ActionProcess{TypeAction: globals.Unknown}, fmt.Errorf("%w: no balance in other networks", globals.ErrInsufficientBalance)
 	}
 	logger.GlobalLogger.Infof("Bridge to LISK. From: %s.", chain)
 	return bridgeToLisk(acc, balance, chain, clients[chain])
 }
 
-func generateBalanceCheck(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generateBalanceCheck(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	return packActionProcessStruct(globals.Balance, "Balances", big.NewInt(0), globals.NULL, globals.NULL), nil
 }
 
@@ -186,7 +206,7 @@ func bridgeToLisk(acc *account.Account, balance *big.Int, chain string, client *
 	}, nil
 }
 
-func generateAirdropChecker(acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
+func generateAirdropChecker(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (ActionProcess, error) {
 	return ActionProcess{
 		TokenFrom:  globals.NULL,
 		TokenTo:    globals.NULL,
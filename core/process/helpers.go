@@ -1,15 +1,20 @@
 package process
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"lisk/account"
+	"lisk/config"
 	"lisk/ethClient"
 	"lisk/globals"
+	"lisk/logger"
 	"lisk/models"
 	"lisk/modules"
+	"lisk/utils"
 	"math/big"
 	"math/rand"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -31,7 +36,27 @@ func determineActionCount(acc *account.Account, selectModule string) int {
 	return totalActions
 }
 
-func getMaxBalance(acc *account.Account, clients map[string]*ethClient.Client) (string, *big.Int, error) {
+// routeNeedsGas reports whether any of the steps sends on-chain transactions.
+func routeNeedsGas(route []config.RouteStep) bool {
+	for _, step := range route {
+		switch step.Module {
+		case "Checker", "Portal_daily_check", "Portal_main_tasks", "BalanceCheck", "AirdropStatus":
+			continue
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func rollStep(step config.RouteStep) bool {
+	if step.Probability == nil {
+		return true
+	}
+	return rand.Float64() < *step.Probability
+}
+
+func getMaxBalance(ctx context.Context, acc *account.Account, clients map[string]*ethClient.Client) (string, *big.Int, error) {
 	var (
 		maxChain string
 		maxBal   = big.NewInt(0)
@@ -42,10 +67,11 @@ func getMaxBalance(acc *account.Account, clients map[string]*ethClient.Client) (
 			continue
 		}
 
-		balance, err := client.BalanceCheck(acc.Address, globals.WETH)
+		balances, err := accountBalances(ctx, acc.Address, client, globals.WETH)
 		if err != nil {
 			return "", nil, fmt.Errorf("getMaxBalance: failed in chain %s: %w", chain, err)
 		}
+		balance := balances[globals.WETH]
 
 		if balance.Cmp(maxBal) > 0 {
 			maxBal.Set(balance)
@@ -79,59 +105,69 @@ func selectTokenFrom(acc *account.Account) common.Address {
 	return globals.WETH
 }
 
+// selectDifferentToken picks a random token other than token. Every pair of
+// globals.Tokens is swappable through the Oku pool graph.
 func selectDifferentToken(token common.Address) common.Address {
-	tokens := []common.Address{
-		globals.LISK,
-		globals.USDT,
-		globals.USDC,
-		globals.WETH,
+	candidates := make([]common.Address, 0, len(globals.Tokens))
+	for _, t := range globals.Tokens {
+		if t != token {
+			candidates = append(candidates, t)
+		}
 	}
 
-	for attempts := 0; attempts < 10; attempts++ {
-		t := tokens[rand.Intn(len(tokens))]
-		if t != token && !excludeSwap(token, t) {
-			return t
+	return candidates[rand.Intn(len(candidates))]
+}
+
+// selectSwapSource picks a random token other than exclude that has a swap
+// range in the config. LISK has none, it is only bought.
+func selectSwapSource(acc *account.Account, exclude common.Address) common.Address {
+	candidates := make([]common.Address, 0, len(globals.Tokens))
+	for _, t := range globals.Tokens {
+		if t != exclude && acc.SwapRange.MinSwapAmount[t] != nil {
+			candidates = append(candidates, t)
 		}
 	}
+	if len(candidates) == 0 {
+		return globals.NULL
+	}
 
-	return globals.USDT
+	return candidates[rand.Intn(len(candidates))]
 }
 
-func excludeSwap(tokenFrom, tokenTo common.Address) bool {
-	excludedPairs := map[common.Address]map[common.Address]bool{
-		globals.USDT: {
-			globals.LISK: true,
-		},
-		globals.USDC: {
-			globals.WETH: true,
-			globals.LISK: true,
-		},
-		globals.WETH: {
-			globals.USDC: true,
-			globals.LISK: true,
-		},
+// accountBalances reads the balances of owner in one Multicall3 batch.
+func accountBalances(ctx context.Context, owner common.Address, client *ethClient.Client, tokens ...common.Address) (map[common.Address]*big.Int, error) {
+	result, failed, err := client.BatchBalances(ctx, []common.Address{owner}, tokens)
+	if err != nil {
+		return nil, err
+	}
+	for _, err := range failed {
+		return nil, err
 	}
 
-	if tos, exists := excludedPairs[tokenFrom]; exists {
-		if _, excluded := tos[tokenTo]; excluded {
-			return true
-		}
+	balances := make(map[common.Address]*big.Int, len(tokens))
+	for key, balance := range result {
+		balances[key.Token] = balance
 	}
-	return false
+	return balances, nil
 }
 
-func canDoActionByBalance(token common.Address, acc *account.Account, client *ethClient.Client) (*big.Int, error) {
-	balance, err := client.BalanceCheck(acc.Address, token)
-	if err != nil {
-		return nil, fmt.Errorf("canDoActionByBalance: balance check failed for %s: %w", acc.Address.Hex(), err)
+func canDoActionByBalance(token common.Address, acc *account.Account, balances map[common.Address]*big.Int) (*big.Int, error) {
+	balance, ok := balances[token]
+	if !ok {
+		return nil, fmt.Errorf("canDoActionByBalance: no balance of %s for %s", token.Hex(), acc.Address.Hex())
 	}
 
 	if !checkMinimalAmount(balance, token) {
-		return nil, fmt.Errorf("canDoActionByBalance: balance too low for token %s, account %s",
-			token.Hex(), acc.Address.Hex())
+		return nil, fmt.Errorf("canDoActionByBalance: %w for token %s, account %s",
+			globals.ErrInsufficientBalance, token.Hex(), acc.Address.Hex())
+	}
+
+	minAmount, maxAmount := acc.SwapRange.MinSwapAmount[token], acc.SwapRange.MaxSwapAmount[token]
+	if minAmount == nil || maxAmount == nil {
+		return nil, fmt.Errorf("canDoActionByBalance: no swap range for token %s", token.Hex())
 	}
 
-	return getRandomValue(acc.SwapRange.MinSwapAmount[token], acc.SwapRange.MaxSwapAmount[token]), nil
+	return getRandomValue(minAmount, maxAmount), nil
 }
 
 func checkMinimalAmount(balance *big.Int, token common.Address) bool {
@@ -177,33 +213,113 @@ func updateLiquidityState(acc *account.Account, actionType globals.ActionType) {
 	acc.LiquidityState.ActionCount++
 }
 
-func validateNativeBalance(addr common.Address, client *ethClient.Client) (*big.Int, error) {
-	balance, err := client.BalanceCheck(addr, globals.WETH)
-	if err != nil {
-		return big.NewInt(0), err
+// addGasSpend adds the receipt of a mined transaction to the module totals of
+// the account.
+func addGasSpend(acc *account.Account, module string, gasUsed uint64, fee *big.Int, failed bool) {
+	spend, exists := acc.GasSpend[module]
+	if !exists {
+		spend = &models.GasSpend{Fees: big.NewInt(0)}
+		acc.GasSpend[module] = spend
 	}
 
-	if balance.Cmp(globals.MinETHForTx) < 0 {
-		return balance, fmt.Errorf("Native(ETH) balance too low")
+	spend.GasUsed += gasUsed
+	spend.Fees.Add(spend.Fees, fee)
+	if failed {
+		spend.FailedTxs++
 	}
+}
 
-	return balance, nil
+// withActionMeta tags the transactions sent with ctx with the action, keeps
+// them within the gas budgets and counts their fees for module.
+func withActionMeta(ctx context.Context, acc *account.Account, module, action, key string) context.Context {
+	return ethClient.WithTxMeta(ctx, ethClient.TxMeta{
+		Module: module,
+		Action: action,
+		Key:    key,
+		CheckCost: func(cost *big.Int) error {
+			return checkGasBudget(acc, cost)
+		},
+		OnReceipt: func(gasUsed uint64, fee *big.Int, failed bool) {
+			addGasSpend(acc, module, gasUsed, fee, failed)
+		},
+	})
 }
 
-func isCriticalError(err error) bool {
-	if err == nil {
-		return false
+// dailyBudgetSkipped reports once that the daily budget is not checked.
+var dailyBudgetSkipped sync.Once
+
+// checkGasBudget returns ErrBudgetExceeded when the fees spent by the account
+// plus cost go over gas_budget_run or gas_budget_daily. The daily spend comes
+// from the transaction journal; without it (dry run) only the run budget is
+// checked.
+func checkGasBudget(acc *account.Account, cost *big.Int) error {
+	runSpent := big.NewInt(0)
+	for _, spend := range acc.GasSpend {
+		runSpent.Add(runSpent, spend.Fees)
 	}
-	errMsg := err.Error()
-	errorSubstrings := []string{
-		"balance too low",
-		"Gas wait timeout has been exceeded",
+
+	if globals.GasBudgetRun != nil && new(big.Int).Add(runSpent, cost).Cmp(globals.GasBudgetRun) > 0 {
+		return fmt.Errorf("%w: spent %s ETH in this run, next tx up to %s ETH, run budget %s ETH", globals.ErrBudgetExceeded,
+			utils.ConvertFromWei(runSpent, 18), utils.ConvertFromWei(cost, 18), utils.ConvertFromWei(globals.GasBudgetRun, 18))
 	}
 
-	for _, substr := range errorSubstrings {
-		if strings.Contains(errMsg, substr) {
-			return true
+	if globals.GasBudgetDaily != nil && ethClient.TxJournal == nil {
+		dailyBudgetSkipped.Do(func() {
+			logger.GlobalLogger.Warnf("No transaction journal, gas_budget_daily is not checked")
+		})
+	} else if globals.GasBudgetDaily != nil {
+		daySpent := ethClient.TxJournal.SpentSince(acc.Address, time.Now().UTC().Truncate(24*time.Hour))
+		if new(big.Int).Add(daySpent, cost).Cmp(globals.GasBudgetDaily) > 0 {
+			return fmt.Errorf("%w: spent %s ETH today, next tx up to %s ETH, daily budget %s ETH", globals.ErrBudgetExceeded,
+				utils.ConvertFromWei(daySpent, 18), utils.ConvertFromWei(cost, 18), utils.ConvertFromWei(globals.GasBudgetDaily, 18))
 		}
 	}
-	return false
+
+	return nil
+}
+
+// checkActionBudget checks the gas budgets against the estimated cost of the
+// next action. Without a budget nothing is requested from the node.
+func checkActionBudget(ctx context.Context, acc *account.Account, client *ethClient.Client) error {
+	if globals.GasBudgetRun == nil && globals.GasBudgetDaily == nil {
+		return nil
+	}
+
+	cost, err := client.EstimateActionCost(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to estimate action cost: %w", err)
+	}
+
+	return checkGasBudget(acc, cost)
+}
+
+// quarantineAccount moves the key of the account from privateKeys.txt to the
+// error file. Keys of keystores and vaults stay where they are, only the
+// address is written to the error file.
+func quarantineAccount(acc *account.Account) error {
+	if acc.RawPK != "" {
+		return utils.ReplacePrivateKey(acc.RawPK, acc.Address.Hex())
+	}
+	return utils.AppendLinesToFile(utils.GetPath("error"), []string{acc.Address.Hex()})
+}
+
+func validateNativeBalance(ctx context.Context, addr common.Address, client *ethClient.Client) (*big.Int, error) {
+	balance, err := client.BalanceCheck(ctx, addr, globals.WETH)
+	if err != nil {
+		return big.NewInt(0), err
+	}
+
+	return balance, checkNativeBalance(balance)
+}
+
+func checkNativeBalance(balance *big.Int) error {
+	if balance.Cmp(globals.MinETHForTx) < 0 {
+		return fmt.Errorf("%w: native (ETH) balance too low", globals.ErrInsufficientBalance)
+	}
+	return nil
+}
+
+// isCriticalError reports errors after which the account cannot continue.
+func isCriticalError(err error) bool {
+	return errors.Is(err, globals.ErrInsufficientBalance) || errors.Is(err, globals.ErrGasTimeout)
 }
@@ -0,0 +1,44 @@
+package process
+
+import (
+	"errors"
+	"lisk/account"
+	"lisk/globals"
+	"lisk/models"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func testSwapAccount() *account.Account {
+	return &account.Account{
+		SwapRange: models.SwapRange{
+			MinSwapAmount: map[common.Address]*big.Int{globals.USDC: big.NewInt(1e6), globals.USDT: big.NewInt(1e6), globals.WETH: big.NewInt(1e15)},
+			MaxSwapAmount: map[common.Address]*big.Int{globals.USDC: big.NewInt(2e6), globals.USDT: big.NewInt(2e6), globals.WETH: big.NewInt(2e15)},
+		},
+	}
+}
+
+func TestSelectSwapSourceSkipsTokensWithoutRange(t *testing.T) {
+	acc := testSwapAccount()
+	for i := 0; i < 100; i++ {
+		switch token := selectSwapSource(acc, globals.WETH); token {
+		case globals.USDC, globals.USDT:
+		default:
+			t.Fatalf("selectSwapSource = %s, want USDC or USDT", token.Hex())
+		}
+	}
+}
+
+func TestCanDoActionByBalanceWithoutRange(t *testing.T) {
+	acc := testSwapAccount()
+	balances := map[common.Address]*big.Int{globals.LISK: big.NewInt(1e18), globals.WETH: big.NewInt(0)}
+
+	if _, err := canDoActionByBalance(globals.LISK, acc, balances); err == nil {
+		t.Fatal("swap from a token without a swap range was accepted")
+	}
+	if _, err := canDoActionByBalance(globals.WETH, acc, balances); !errors.Is(err, globals.ErrInsufficientBalance) {
+		t.Fatalf("empty balance = %v, want ErrInsufficientBalance", err)
+	}
+}
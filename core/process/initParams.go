@@ -4,6 +4,7 @@ import (
 	"lisk/config"
 	"lisk/globals"
 	"lisk/logger"
+	"lisk/retry"
 	"lisk/utils"
 	"math/big"
 	"time"
@@ -24,11 +25,39 @@ func InitGlobals(cfg *config.Config) {
 	updateMapValue(globals.MinBalances, globals.USDT, cfg.MinUSDTForSwap, 6, "USDTAmount")
 	updateMapValue(globals.MinBalances, globals.USDC, cfg.MinUSDTForSwap, 6, "USDCAmount")
 	initGlobalWei(&globals.AttentionGwei, cfg.AttentionGwei, 9, "AttantionGwei")
+	initGlobalWei(&globals.AttentionTxCost, cfg.AttentionTxCost, 18, "AttentionTxCost")
+	initGlobalWei(&globals.GasBudgetDaily, cfg.GasBudgetDaily, 18, "GasBudgetDaily")
+	initGlobalWei(&globals.GasBudgetRun, cfg.GasBudgetRun, 18, "GasBudgetRun")
 	initGlobalWei(&globals.IonicBorrow, cfg.IonicBorrow, 18, "IonicBorrow")
 	initGlobalWei(&globals.IonicSupply, cfg.IonicSupply, 6, "IonicSupply")
 
 	initGlobalDuration(&globals.AttentionTime, cfg.AttentionTime, "AttantionTime")
 	initGlobalDuration(&globals.MaxAttentionTime, cfg.MaxAttentionTime, "MaxAttantionTime")
+	initGlobalDuration(&globals.TxReplaceAfter, cfg.TxReplaceAfter, "TxReplaceAfter")
+	initIntValue(&globals.TxFeeBumpPercent, cfg.TxFeeBumpPercent)
+	initIntValue(&globals.TxMaxFeeBumps, cfg.TxMaxFeeBumps)
+	initOptionalInt(&globals.SlippageBps, cfg.SlippageBps)
+	initOptionalInt(&globals.MaxPriceImpactBps, cfg.MaxPriceImpactBps)
+	if globals.SlippageBps < 0 || globals.SlippageBps >= 10000 {
+		logger.GlobalLogger.Warnf("slippage_bps %d is out of range 0-9999, using 50", globals.SlippageBps)
+		globals.SlippageBps = 50
+	}
+	if globals.MaxPriceImpactBps < 0 {
+		logger.GlobalLogger.Warnf("max_price_impact_bps %d is negative, using 300", globals.MaxPriceImpactBps)
+		globals.MaxPriceImpactBps = 300
+	}
+	if globals.TxFeeBumpPercent < 10 {
+		logger.GlobalLogger.Warnf("tx_fee_bump_percent %d is below the 10%% accepted by nodes, using 10", globals.TxFeeBumpPercent)
+		globals.TxFeeBumpPercent = 10
+	}
+
+	if err := retry.SetPolicies(cfg.RetryPolicies); err != nil {
+		logger.GlobalLogger.Errorf("failed to apply retry policies, defaults are used: %v", err)
+	}
+
+	if cfg.DryRun {
+		globals.DryRun = true
+	}
 }
 
 func parseDate(dateStr string) (time.Time, error) {
@@ -69,3 +98,11 @@ func initIntValue(globalVar *int, value int) {
 		*globalVar = value
 	}
 }
+
+// initOptionalInt sets globalVar when the field is present in the config, zero
+// included.
+func initOptionalInt(globalVar *int, value *int) {
+	if value != nil {
+		*globalVar = *value
+	}
+}
@@ -0,0 +1,28 @@
+package process
+
+import (
+	"encoding/json"
+	"lisk/config"
+	"lisk/globals"
+	"testing"
+)
+
+func TestInitGlobalsZeroBps(t *testing.T) {
+	oldSlippage, oldImpact := globals.SlippageBps, globals.MaxPriceImpactBps
+	t.Cleanup(func() { globals.SlippageBps, globals.MaxPriceImpactBps = oldSlippage, oldImpact })
+
+	var cfg config.Config
+	if err := json.Unmarshal([]byte(`{"slippage_bps":0,"max_price_impact_bps":0}`), &cfg); err != nil {
+		t.Fatal(err)
+	}
+	InitGlobals(&cfg)
+	if globals.SlippageBps != 0 || globals.MaxPriceImpactBps != 0 {
+		t.Fatalf("configured 0 gave slippage %d, impact %d", globals.SlippageBps, globals.MaxPriceImpactBps)
+	}
+
+	globals.SlippageBps, globals.MaxPriceImpactBps = 50, 300
+	InitGlobals(&config.Config{})
+	if globals.SlippageBps != 50 || globals.MaxPriceImpactBps != 300 {
+		t.Fatalf("omitted fields changed slippage to %d, impact to %d", globals.SlippageBps, globals.MaxPriceImpactBps)
+	}
+}
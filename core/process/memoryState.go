@@ -1,17 +1,49 @@
 package process
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"lisk/account"
+	"lisk/config"
+	"lisk/logger"
+	"lisk/models"
 	"os"
+	"sort"
 	"sync"
 )
 
+// stateVersion is the schema version of the state file. Version 1 was a plain
+// JSON array of account states without strategy data.
+const stateVersion = 2
+
+// maxSavedSwaps limits the swap history kept in the state file. Only the last
+// swaps are used to choose the next one.
+const maxSavedSwaps = 10
+
+type stateFile struct {
+	Version  int            `json:"version"`
+	Accounts []AccountState `json:"accounts"`
+}
+
+// StrategyState is the per-module history of an account that the action
+// generators depend on.
+type StrategyState struct {
+	LastSwaps      []models.SwapPair      `json:"last_swaps,omitempty"`
+	WrapHistory    models.WrapHistory     `json:"wrap_history"`
+	LiquidityState *models.LiquidityState `json:"liquidity_state,omitempty"`
+}
+
 type AccountState struct {
-	AccountAddress  string `json:"address"`
-	LastActionIndex int    `json:"last_action_index"`
-	Module          string `json:"module"`
+	AccountAddress  string             `json:"address"`
+	LastActionIndex int                `json:"last_action_index"`
+	Module          string             `json:"module"`
+	Step            int                `json:"step"`
+	Route           []config.RouteStep `json:"route,omitempty"`
+	PendingTx       string             `json:"pending_tx,omitempty"`
+	PendingChain    string             `json:"pending_chain,omitempty"`
+	Strategy        StrategyState      `json:"strategy"`
 }
 
 type Memory struct {
@@ -20,6 +52,8 @@ type Memory struct {
 	states        map[string]*AccountState
 }
 
+// NewMemory loads saved account states from stateFilePath. An empty path gives
+// an in-memory state that is never written to disk.
 func NewMemory(stateFilePath string) (*Memory, error) {
 	m := &Memory{
 		StateFilePath: stateFilePath,
@@ -37,6 +71,10 @@ func (m *Memory) loadFromFile() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if m.StateFilePath == "" {
+		return nil
+	}
+
 	file, err := os.Open(m.StateFilePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -46,17 +84,18 @@ func (m *Memory) loadFromFile() error {
 	}
 	defer file.Close()
 
-	bytes, err := ioutil.ReadAll(file)
+	data, err := ioutil.ReadAll(file)
 	if err != nil {
 		return fmt.Errorf("не удалось прочитать файл состояния: %w", err)
 	}
 
-	if len(bytes) == 0 {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
 		return nil
 	}
 
-	var states []AccountState
-	if err := json.Unmarshal(bytes, &states); err != nil {
+	states, err := decodeStates(data)
+	if err != nil {
 		return fmt.Errorf("не удалось распарсить файл состояния: %w", err)
 	}
 
@@ -68,19 +107,60 @@ func (m *Memory) loadFromFile() error {
 	return nil
 }
 
+// decodeStates reads every known schema version of the state file.
+func decodeStates(data []byte) ([]AccountState, error) {
+	if data[0] == '[' || bytes.Equal(data, []byte("null")) {
+		var states []AccountState
+		if err := json.Unmarshal(data, &states); err != nil {
+			return nil, err
+		}
+		if len(states) > 0 {
+			logger.GlobalLogger.Infof("State file migrated from version 1 to %d, strategy history starts empty", stateVersion)
+		}
+		return states, nil
+	}
+
+	var file stateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	if file.Version > stateVersion {
+		return nil, fmt.Errorf("unsupported state file version %d (max %d)", file.Version, stateVersion)
+	}
+
+	return file.Accounts, nil
+}
+
 func (m *Memory) saveToFile() error {
+	if m.StateFilePath == "" {
+		return nil
+	}
 
-	var states []AccountState
+	file := stateFile{
+		Version:  stateVersion,
+		Accounts: make([]AccountState, 0, len(m.states)),
+	}
 	for _, state := range m.states {
-		states = append(states, *state)
+		file.Accounts = append(file.Accounts, *state)
 	}
 
-	bytes, err := json.MarshalIndent(states, "", "  ")
+	sort.Slice(file.Accounts, func(i, j int) bool {
+		return file.Accounts[i].AccountAddress < file.Accounts[j].AccountAddress
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
 	if err != nil {
 		return fmt.Errorf("не удалось сериализовать состояния: %w", err)
 	}
 
-	if err := ioutil.WriteFile(m.StateFilePath, bytes, 0644); err != nil {
+	// A write interrupted by shutdown must not truncate the resume file.
+	tempPath := m.StateFilePath + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("не удалось записать файл состояния: %w", err)
+	}
+	if err := os.Rename(tempPath, m.StateFilePath); err != nil {
+		os.Remove(tempPath)
 		return fmt.Errorf("не удалось записать файл состояния: %w", err)
 	}
 
@@ -98,6 +178,22 @@ func (m *Memory) LoadState(accountAddress string) (*AccountState, error) {
 	return state, nil
 }
 
+func (m *Memory) States() []AccountState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	states := make([]AccountState, 0, len(m.states))
+	for _, state := range m.states {
+		states = append(states, *state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].AccountAddress < states[j].AccountAddress
+	})
+
+	return states
+}
+
 func (m *Memory) IsStateFileNotEmpty() (bool, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -105,23 +201,100 @@ func (m *Memory) IsStateFileNotEmpty() (bool, error) {
 	return len(m.states) > 0, nil
 }
 
-func (m *Memory) UpdateState(accountAddress string, module string, actionIndex int) error {
+// UpdateState saves the progress of the account together with its current
+// strategy history.
+func (m *Memory) UpdateState(acc *account.Account, route []config.RouteStep, step, actionIndex int) error {
 	m.mu.Lock()
 
 	defer m.mu.Unlock()
 
-	state, exists := m.states[accountAddress]
+	state := m.stateFor(acc)
+
+	state.LastActionIndex = actionIndex
+	state.Module = route[step].Module
+	state.Step = step
+	state.Route = route
+	state.PendingTx = ""
+	state.PendingChain = ""
+
+	return m.saveToFile()
+}
+
+// ActionKey identifies an action of the route in the transaction journal.
+func (m *Memory) ActionKey(step, actionIndex int) string {
+	return fmt.Sprintf("%s%d/%d", m.JournalPrefix(), step, actionIndex)
+}
+
+// JournalPrefix is the common prefix of the journal keys of this state file.
+func (m *Memory) JournalPrefix() string {
+	return m.StateFilePath + "#"
+}
+
+// SetPending records a transaction of the current action that was broadcast
+// but not mined yet, so a resumed run checks it before repeating the action.
+func (m *Memory) SetPending(acc *account.Account, route []config.RouteStep, step, actionIndex int, chain, txHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := m.stateFor(acc)
+
+	state.LastActionIndex = actionIndex
+	state.Module = route[step].Module
+	state.Step = step
+	state.Route = route
+	state.PendingTx = txHash
+	state.PendingChain = chain
+
+	return m.saveToFile()
+}
+
+// stateFor returns the state of the account with a fresh copy of its strategy.
+// Callers must hold m.mu.
+func (m *Memory) stateFor(acc *account.Account) *AccountState {
+	address := acc.Address.Hex()
+
+	state, exists := m.states[address]
 	if !exists {
-		m.states[accountAddress] = &AccountState{
-			AccountAddress:  accountAddress,
-			LastActionIndex: actionIndex,
-			Module:          module,
-		}
-	} else {
-		state.LastActionIndex = actionIndex
-		state.Module = module
+		state = &AccountState{AccountAddress: address}
+		m.states[address] = state
 	}
 
+	swaps := acc.LastSwaps
+	if len(swaps) > maxSavedSwaps {
+		swaps = swaps[len(swaps)-maxSavedSwaps:]
+	}
+
+	state.Strategy = StrategyState{
+		LastSwaps:   append([]models.SwapPair(nil), swaps...),
+		WrapHistory: acc.WrapHistory,
+	}
+	if acc.LiquidityState != nil {
+		liquidity := *acc.LiquidityState
+		state.Strategy.LiquidityState = &liquidity
+	}
+
+	return state
+}
+
+// RestoreStrategy loads the saved strategy history into the account.
+func (s *AccountState) RestoreStrategy(acc *account.Account) {
+	if len(s.Strategy.LastSwaps) > 0 {
+		acc.LastSwaps = append([]models.SwapPair(nil), s.Strategy.LastSwaps...)
+	}
+	if s.Strategy.WrapHistory.LastAction != "" {
+		acc.WrapHistory = s.Strategy.WrapHistory
+	}
+	if s.Strategy.LiquidityState != nil {
+		liquidity := *s.Strategy.LiquidityState
+		acc.LiquidityState = &liquidity
+	}
+}
+
+// Flush writes the current states to disk.
+func (m *Memory) Flush() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	return m.saveToFile()
 }
 
@@ -139,5 +312,11 @@ func (m *Memory) ClearAllStates() error {
 	defer m.mu.Unlock()
 
 	m.states = make(map[string]*AccountState)
-	return os.Truncate(m.StateFilePath, 0)
+	if m.StateFilePath == "" {
+		return nil
+	}
+	if err := os.Truncate(m.StateFilePath, 0); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
 }
@@ -2,14 +2,16 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"lisk/account"
+	"lisk/config"
 	"lisk/ethClient"
 	"lisk/globals"
 	"lisk/logger"
 	"lisk/modules"
+	"lisk/retry"
 	"lisk/utils"
-	"log"
 	"math/big"
 	"sync"
 	"time"
@@ -26,37 +28,68 @@ type ActionProcess struct {
 	Module     string
 }
 
-func ProcessAccounts(accs []*account.Account, selectModule string, mod map[string]modules.ModulesFasad, clients map[string]*ethClient.Client, memory *Memory) error {
+// RunErrors is returned when some accounts of a run failed. Failed is keyed by
+// account address.
+type RunErrors struct {
+	Failed map[string]error
+}
+
+func (e *RunErrors) Error() string {
+	return fmt.Sprintf("ProcessAccount encountered %d errors", len(e.Failed))
+}
+
+func ProcessAccounts(ctx context.Context, accs []*account.Account, selectModule string, mod map[string]modules.ModulesFasad, clients map[string]*ethClient.Client, memory *Memory) error {
+	var route []config.RouteStep
+	if selectModule != "" {
+		route = []config.RouteStep{{Module: selectModule}}
+	}
+
+	return ProcessRoute(ctx, accs, route, mod, clients, memory)
+}
+
+// ProcessRoute walks every account through the route steps in order. A nil
+// route resumes each account from its saved state. Cancelling ctx stops the
+// accounts after their current transaction and keeps the progress.
+func ProcessRoute(ctx context.Context, accs []*account.Account, route []config.RouteStep, mod map[string]modules.ModulesFasad, clients map[string]*ethClient.Client, memory *Memory) error {
 	if err := validateInputData(accs, mod, clients); err != nil {
 		return err
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	if err := ValidateRoute(route); err != nil {
+		return err
+	}
+
+	if batch, ok := batchModuleFor(route, mod); ok {
+		return processBatch(ctx, accs, route[0].Module, batch)
+	}
 
-	g, ctx := errgroup.WithContext(ctx)
+	g, gctx := errgroup.WithContext(ctx)
 
 	semaphore := make(chan struct{}, globals.GorutinesCount)
 
 	var (
 		mu       sync.Mutex
-		nonFatal []error
+		nonFatal = make(map[string]error)
 	)
 
 	for _, acc := range accs {
 		currentAcc := acc
 
 		g.Go(func() error {
-			semaphore <- struct{}{}
+			select {
+			case semaphore <- struct{}{}:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
 			defer func() { <-semaphore }()
 
-			if ctx.Err() != nil {
-				return ctx.Err()
+			if gctx.Err() != nil {
+				return gctx.Err()
 			}
 
-			if err := processSingleAccount(ctx, currentAcc, selectModule, mod, clients, memory); err != nil {
+			if err := processSingleAccount(gctx, currentAcc, route, mod, clients, memory); err != nil {
 				mu.Lock()
-				nonFatal = append(nonFatal, err)
+				nonFatal[currentAcc.Address.Hex()] = err
 				mu.Unlock()
 			}
 
@@ -65,23 +98,92 @@ func ProcessAccounts(accs []*account.Account, selectModule string, mod map[strin
 
 	}
 
-	if err := g.Wait(); err != nil {
-		return fmt.Errorf("ProcessAccount interrupted: %w", err)
+	waitErr := g.Wait()
+
+	if err := memory.Flush(); err != nil {
+		logger.GlobalLogger.Warnf("Failed to flush state file: %v", err)
+	}
+
+	if !globals.DryRun {
+		if err := WriteWeeklyStats(accs); err != nil {
+			return fmt.Errorf("failed to write weekly stats: %w", err)
+		}
+	}
+
+	if waitErr != nil {
+		return fmt.Errorf("ProcessAccount interrupted: %w", waitErr)
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("ProcessAccount interrupted: %w", ctx.Err())
 	}
 
 	if len(nonFatal) > 0 {
-		return fmt.Errorf("ProcessAccount encountered %d errors", len(nonFatal))
+		return &RunErrors{Failed: nonFatal}
+	}
+
+	return nil
+}
+
+// batchModules maps route modules to the registered modules that can serve
+// all accounts with one BatchAction.
+var batchModules = map[string]string{
+	"BalanceCheck": "Balances",
+}
+
+// batchModuleFor returns the batch module of a route that consists of a
+// single batchable step.
+func batchModuleFor(route []config.RouteStep, mod map[string]modules.ModulesFasad) (modules.BatchModule, bool) {
+	if len(route) != 1 || route[0].Probability != nil {
+		return nil, false
+	}
+
+	name, exists := batchModules[route[0].Module]
+	if !exists {
+		return nil, false
+	}
+
+	batch, ok := mod[name].(modules.BatchModule)
+	return batch, ok
+}
+
+func processBatch(ctx context.Context, accs []*account.Account, module string, batch modules.BatchModule) error {
+	if err := batch.BatchAction(ctx, accs); err != nil {
+		return fmt.Errorf("%s batch failed: %w", module, err)
+	}
+
+	for _, acc := range accs {
+		acc.Stats[module]++
 	}
 
-	if err := WriteWeeklyStats(accs); err != nil {
-		return fmt.Errorf("failed to write weekly stats: %w", err)
+	if !globals.DryRun {
+		if err := WriteWeeklyStats(accs); err != nil {
+			return fmt.Errorf("failed to write weekly stats: %w", err)
+		}
 	}
 
+	logger.GlobalLogger.Infof("%s is done for %d accounts", module, len(accs))
+	return nil
+}
+
+func ValidateRoute(route []config.RouteStep) error {
+	for i, step := range route {
+		if !HasModule(step.Module) {
+			return fmt.Errorf("route step %d: unknown module '%s'", i+1, step.Module)
+		}
+	}
 	return nil
 }
 
-func processSingleAccount(ctx context.Context, acc *account.Account, selectModule string, mod map[string]modules.ModulesFasad, clients map[string]*ethClient.Client, memory *Memory) error {
-	if err := performActions(acc, selectModule, mod, clients, memory); err != nil {
+func processSingleAccount(ctx context.Context, acc *account.Account, route []config.RouteStep, mod map[string]modules.ModulesFasad, clients map[string]*ethClient.Client, memory *Memory) error {
+	if err := performRoute(ctx, acc, route, mod, clients, memory); err != nil {
+		if ctx.Err() != nil {
+			logger.GlobalLogger.Warnf("[%v] Stopped by shutdown, progress saved", acc.Address.Hex())
+			return fmt.Errorf("[%v] interrupted: %w", acc.Address.Hex(), err)
+		}
+		if errors.Is(err, globals.ErrBudgetExceeded) {
+			logger.GlobalLogger.Warnf("[%v] Stopped, progress saved: %v", acc.Address.Hex(), err)
+			return fmt.Errorf("[%v] stopped: %w", acc.Address.Hex(), err)
+		}
 		logger.GlobalLogger.Errorf("[%v] failed to perform actions: %v", acc.Address.Hex(), err)
 		return fmt.Errorf("[%v] performActions error: %w", acc.Address.Hex(), err)
 	}
@@ -91,11 +193,57 @@ func processSingleAccount(ctx context.Context, acc *account.Account, selectModul
 	return nil
 }
 
-func performActions(acc *account.Account, selectModule string, mod map[string]modules.ModulesFasad, clients map[string]*ethClient.Client, memory *Memory) error {
-	if _, err := validateNativeBalance(acc.Address, clients["lisk"]); err != nil {
-		if isCriticalError(err) && (selectModule != "Checker" && selectModule != "Portal_daily_check" && selectModule != "Portal_main_tasks" && selectModule != "BalanceCheck" && selectModule != "AirdropStatus") {
+func performRoute(ctx context.Context, acc *account.Account, route []config.RouteStep, mod map[string]modules.ModulesFasad, clients map[string]*ethClient.Client, memory *Memory) error {
+	state, err := memory.LoadState(acc.Address.Hex())
+	if err != nil {
+		return fmt.Errorf("failed to load state for account %s: %v", acc.Address.Hex(), err)
+	}
+
+	var step, successfulActions int
+	if state != nil {
+		switch {
+		case len(state.Route) > 0:
+			route = state.Route
+		case state.Module != "":
+			route = []config.RouteStep{{Module: state.Module}}
+		}
+		step = state.Step
+		successfulActions = state.LastActionIndex
+		state.RestoreStrategy(acc)
+		logger.GlobalLogger.Infof("[%s] Resuming from step %d, action index %d", acc.Address.Hex(), step+1, successfulActions)
+	} else {
+		// Transactions of an abandoned run must not complete actions of this one.
+		ethClient.TxJournal.SettlePrefix(acc.Address, memory.JournalPrefix())
+	}
+
+	if len(route) == 0 {
+		return fmt.Errorf("no module selected and no saved state for account")
+	}
+
+	if state != nil && step < len(route) {
+		key := memory.ActionKey(step, successfulActions)
+		resolveCtx := withActionMeta(ctx, acc, route[step].Module, "", key)
+		done, err := resolveUnfinishedAction(resolveCtx, acc, state, key, clients)
+		if err != nil {
+			return err
+		}
+		ethClient.TxJournal.Settle(acc.Address, key)
+		if done {
+			successfulActions++
+			if err := memory.UpdateState(acc, route, step, successfulActions); err != nil {
+				logger.GlobalLogger.Warnf("[%s] Failed to update state: %v", acc.Address.Hex(), err)
+			}
+			acc.Stats[route[step].Module]++
+		}
+	}
+
+	if _, err := validateNativeBalance(ctx, acc.Address, clients["lisk"]); err != nil {
+		if isCriticalError(err) && routeNeedsGas(route[step:]) {
 			logger.GlobalLogger.Warnf("[%v] Insufficient ETH  balance. Stop trying.", acc.Address.Hex())
-			if err := utils.ReplacePrivateKey(acc.RawPK, acc.Address.Hex()); err != nil {
+			if globals.DryRun {
+				return err
+			}
+			if err := quarantineAccount(acc); err != nil {
 				logger.GlobalLogger.Errorf("[%v] Failed to replace private key: %v", acc.Address, err)
 				return err
 			}
@@ -103,28 +251,56 @@ func performActions(acc *account.Account, selectModule string, mod map[string]mo
 		}
 	}
 
-	state, err := memory.LoadState(acc.Address.Hex())
-	if err != nil {
-		return fmt.Errorf("failed to load state for account %s: %v", acc.Address.Hex(), err)
+	for ; step < len(route); step++ {
+		if successfulActions == 0 && !rollStep(route[step]) {
+			logger.GlobalLogger.Infof("[%s] Step %d (%s) skipped by probability", acc.Address.Hex(), step+1, route[step].Module)
+			continue
+		}
+
+		if len(route) > 1 {
+			logger.GlobalLogger.Infof("[%s] Route step %d/%d: %s", acc.Address.Hex(), step+1, len(route), route[step].Module)
+		}
+
+		if err := performActions(ctx, acc, route, step, successfulActions, mod, clients, memory); err != nil {
+			return err
+		}
+		successfulActions = 0
 	}
 
-	const maxRetriesPerAction = 3
-	var successfulActions int
-	if state != nil {
-		successfulActions = state.LastActionIndex
-		selectModule = state.Module
-		logger.GlobalLogger.Infof("[%s] Resuming from action index %d", acc.Address.Hex(), successfulActions)
+	if err := memory.ClearState(acc.Address.Hex()); err != nil {
+		logger.GlobalLogger.Warnf("[%s] Failed to clear state: %v", acc.Address.Hex(), err)
+	}
+	logger.GlobalLogger.Infof("[%s] All actions completed. State cleared.", acc.Address.Hex())
+
+	return nil
+}
+
+func performActions(ctx context.Context, acc *account.Account, route []config.RouteStep, step, successfulActions int, mod map[string]modules.ModulesFasad, clients map[string]*ethClient.Client, memory *Memory) error {
+	selectModule := route[step].Module
+
+	totalActions := route[step].Actions
+	if totalActions == 0 {
+		totalActions = determineActionCount(acc, selectModule)
 	}
 
-	totalActions := determineActionCount(acc, selectModule)
-	log.Println("total", totalActions)
 	for successfulActions < totalActions {
 		sleepDuration := generateTimeWindow(acc.ActionsTime, totalActions)[0]
 
 		logger.GlobalLogger.Infof("[%v] Sleep before action %v", acc.Address, sleepDuration)
-		time.Sleep(sleepDuration)
+		if err := utils.SleepContext(ctx, sleepDuration); err != nil {
+			return err
+		}
 
-		action, err := generateNextAction(acc, selectModule, clients)
+		if routeNeedsGas(route[step : step+1]) {
+			if err := checkActionBudget(ctx, acc, clients["lisk"]); err != nil {
+				if errors.Is(err, globals.ErrBudgetExceeded) {
+					return err
+				}
+				logger.GlobalLogger.Warnf("[%v] Gas budget not checked: %v", acc.Address, err)
+			}
+		}
+
+		action, err := generateNextAction(ctx, acc, selectModule, clients)
 		if err != nil {
 			if isCriticalError(err) {
 				logger.GlobalLogger.Warnf("[%v] Insufficient balance for swap. Stop trying.", acc.Address.Hex())
@@ -142,26 +318,59 @@ func performActions(acc *account.Account, selectModule string, mod map[string]mo
 				break actionLoop
 			}
 
-			if err := moduleFasad.Action(action.TokenFrom, action.TokenTo, action.Amount, acc, action.TypeAction); err != nil {
+			actionKey := memory.ActionKey(step, successfulActions)
+			actionCtx := withActionMeta(ctx, acc, selectModule, string(action.TypeAction), actionKey)
+
+			if err := moduleFasad.Action(actionCtx, action.TokenFrom, action.TokenTo, action.Amount, acc, action.TypeAction); err != nil {
 				if isCriticalError(err) {
-					logger.GlobalLogger.Warnf("[%v] Insufficient balance for swap. Stop trying.", acc.Address.Hex())
+					logger.GlobalLogger.Warnf("[%v] %v. Stop trying.", acc.Address.Hex(), err)
 					return err
 				}
 
-				retryCount++
-				if retryCount <= maxRetriesPerAction {
-					logger.GlobalLogger.Warnf("[%v] Action failed for: %v. Retry %d of %d...", acc.Address, err, retryCount, maxRetriesPerAction)
-					time.Sleep(5 * time.Second)
-					continue
-				} else {
-					logger.GlobalLogger.Errorf("[%v] Action failed after %d retries. Skip action.", acc.Address, maxRetriesPerAction)
+				if errors.Is(err, globals.ErrBudgetExceeded) {
+					ethClient.TxJournal.Settle(acc.Address, actionKey)
+					return err
+				}
+
+				var pendingErr *ethClient.TxPendingError
+				if errors.As(err, &pendingErr) {
+					logger.GlobalLogger.Warnf("[%v] %v. Recorded as pending, the account is stopped.", acc.Address.Hex(), pendingErr)
+					if err := memory.SetPending(acc, route, step, successfulActions, pendingErr.Chain, pendingErr.Hash.Hex()); err != nil {
+						logger.GlobalLogger.Warnf("[%s] Failed to update state: %v", acc.Address.Hex(), err)
+					}
+					return err
+				}
+
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				ethClient.TxJournal.Settle(acc.Address, actionKey)
+
+				if !retry.Action.Matches(err) {
+					logger.GlobalLogger.Warnf("[%v] Action cannot be done (%s): %v. Skip action.", acc.Address, retry.Classify(err), err)
+					break actionLoop
+				}
+
+				if !retry.Action.ShouldRetry(err, retryCount+1) {
+					logger.GlobalLogger.Errorf("[%v] Action failed after %d retries: %v. Skip action.", acc.Address, retryCount, err)
 					break actionLoop
 				}
+
+				retryCount++
+				acc.Retries[selectModule]++
+				retryDelay := retry.Action.Delay(retryCount)
+				logger.GlobalLogger.Warnf("[%v] Action failed (%s): %v. Retry %d of %d in %v...", acc.Address, retry.Classify(err), err, retryCount, retry.Action.MaxAttempts-1, retryDelay.Round(time.Second))
+				if err := utils.SleepContext(ctx, retryDelay); err != nil {
+					return err
+				}
+				continue
 			}
 
-			if err := memory.UpdateState(acc.Address.Hex(), selectModule, successfulActions+1); err != nil {
+			if err := memory.UpdateState(acc, route, step, successfulActions+1); err != nil {
 				logger.GlobalLogger.Warnf("[%s] Failed to update state: %v", acc.Address.Hex(), err)
 			}
+			ethClient.TxJournal.Settle(acc.Address, actionKey)
 			acc.Stats[selectModule]++
 			successfulActions++
 
@@ -170,11 +379,56 @@ func performActions(acc *account.Account, selectModule string, mod map[string]mo
 		}
 	}
 
-	if successfulActions == totalActions {
-		if err := memory.ClearState(acc.Address.Hex()); err != nil {
-			logger.GlobalLogger.Warnf("[%s] Failed to clear state: %v", acc.Address.Hex(), err)
+	return nil
+}
+
+// resolveUnfinishedAction checks the transactions of the action the previous
+// run stopped at: the pending tx of the state file or, when the run stopped
+// before recording it, the transactions of the journal. It reports whether the
+// action was executed.
+func resolveUnfinishedAction(ctx context.Context, acc *account.Account, state *AccountState, key string, clients map[string]*ethClient.Client) (bool, error) {
+	if state.PendingTx != "" {
+		return resolvePendingTx(ctx, acc, state.PendingChain, state.PendingTx, clients)
+	}
+
+	entries := ethClient.TxJournal.Unsettled(acc.Address, key)
+	if len(entries) == 0 {
+		return false, nil
+	}
+
+	for _, entry := range entries {
+		if entry.Status == ethClient.TxStatusSuccess {
+			logger.GlobalLogger.Infof("[%s] Journal: tx %s of the last action was mined, action counted", acc.Address.Hex(), entry.Hash)
+			return true, nil
 		}
-		logger.GlobalLogger.Infof("[%s] All actions completed. State cleared.", acc.Address.Hex())
 	}
-	return nil
+
+	logger.GlobalLogger.Infof("[%s] Journal: tx %s of the last action was sent but not confirmed", acc.Address.Hex(), entries[0].Hash)
+	return resolvePendingTx(ctx, acc, entries[0].Chain, entries[0].Hash, clients)
+}
+
+// resolvePendingTx checks a transaction that was still pending when the
+// previous run stopped. It reports whether the action was executed.
+func resolvePendingTx(ctx context.Context, acc *account.Account, chain, txHash string, clients map[string]*ethClient.Client) (bool, error) {
+	client, exists := clients[chain]
+	if !exists {
+		return false, fmt.Errorf("no client for chain '%s' of pending tx %s", chain, txHash)
+	}
+
+	logger.GlobalLogger.Infof("[%s] Checking pending tx %s", acc.Address.Hex(), txHash)
+	err := client.WaitForTransaction(ctx, common.HexToHash(txHash), 2*time.Minute)
+
+	var pendingErr *ethClient.TxPendingError
+	switch {
+	case err == nil:
+		logger.GlobalLogger.Infof("[%s] Pending tx %s was mined, action counted", acc.Address.Hex(), txHash)
+		return true, nil
+	case errors.As(err, &pendingErr):
+		return false, fmt.Errorf("tx %s is still pending, resolve it before resuming: %w", txHash, err)
+	case ctx.Err() != nil:
+		return false, ctx.Err()
+	default:
+		logger.GlobalLogger.Warnf("[%s] Pending tx %s failed (%v), the action will be repeated", acc.Address.Hex(), txHash, err)
+		return false, nil
+	}
 }
@@ -5,9 +5,18 @@ import (
 	"lisk/account"
 	"lisk/models"
 	"lisk/utils"
+	"math/big"
+	"sync"
 )
 
+// statsMu serializes the read-modify-write of the stats file: jobs of the
+// daemon run concurrently and share it.
+var statsMu sync.Mutex
+
 func WriteWeeklyStats(accounts []*account.Account) error {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
 	csvFilePath := utils.GetPath("stats")
 
 	statsMap, err := utils.ReadStatsFromCSV(csvFilePath)
@@ -21,7 +30,19 @@ func WriteWeeklyStats(accounts []*account.Account) error {
 	for _, acc := range accounts {
 		address := acc.Address.Hex()
 
-		for moduleName, delta := range acc.Stats {
+		moduleNames := make(map[string]bool, len(acc.Stats))
+		for moduleName := range acc.Stats {
+			moduleNames[moduleName] = true
+		}
+		for moduleName := range acc.Retries {
+			moduleNames[moduleName] = true
+		}
+		for moduleName := range acc.GasSpend {
+			moduleNames[moduleName] = true
+		}
+
+		for moduleName := range moduleNames {
+			delta := acc.Stats[moduleName]
 			if delta < 0 {
 				delta = 0
 			}
@@ -45,6 +66,16 @@ func WriteWeeklyStats(accounts []*account.Account) error {
 			}
 
 			oldRecord.TotalSuccess += delta
+			oldRecord.Retries += acc.Retries[moduleName]
+
+			if oldRecord.FeesWei == nil {
+				oldRecord.FeesWei = big.NewInt(0)
+			}
+			if spend, exists := acc.GasSpend[moduleName]; exists {
+				oldRecord.GasUsed += spend.GasUsed
+				oldRecord.FeesWei.Add(oldRecord.FeesWei, spend.Fees)
+				oldRecord.FailedTxs += spend.FailedTxs
+			}
 
 			statsMap[key] = oldRecord
 		}
@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"lisk/config"
+	"lisk/logger"
+	"lisk/utils"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RunFunc executes one job for the given keys (private keys or addresses of
+// loaded accounts) and returns the addresses of the accounts that were
+// processed successfully.
+type RunFunc func(ctx context.Context, job config.Job, keys []string) ([]string, error)
+
+type Scheduler struct {
+	schedule    *config.Schedule
+	defaultKeys []string
+	crons       map[string]*cronExpr
+	state       *runState
+	run         RunFunc
+}
+
+func New(schedule *config.Schedule, defaultKeys []string, run RunFunc) (*Scheduler, error) {
+	crons := make(map[string]*cronExpr, len(schedule.Jobs))
+	for _, job := range schedule.Jobs {
+		expr, err := parseCron(job.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("job %s: %w", job.Name, err)
+		}
+		crons[job.Name] = expr
+	}
+
+	statePath := schedule.StateFile
+	if statePath == "" {
+		statePath = utils.GetPath("schedule_state")
+	}
+
+	state, err := loadRunState(statePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scheduler{
+		schedule:    schedule,
+		defaultKeys: defaultKeys,
+		crons:       crons,
+		state:       state,
+		run:         run,
+	}, nil
+}
+
+// Start runs every job on its own schedule until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	for _, job := range s.schedule.Jobs {
+		job := job
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}()
+	}
+
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job config.Job) {
+	for {
+		startAt := s.nextStart(job, time.Now().UTC())
+		logger.GlobalLogger.Infof("[SCHEDULER] Job %s: next start at %s", job.Name, startAt.Format(time.RFC3339))
+
+		timer := time.NewTimer(time.Until(startAt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.runJob(ctx, job); err != nil {
+			logger.GlobalLogger.Errorf("[SCHEDULER] Job %s failed: %v", job.Name, err)
+		}
+	}
+}
+
+func (s *Scheduler) nextStart(job config.Job, now time.Time) time.Time {
+	next := s.crons[job.Name].next(now)
+	if job.WindowMinutes > 0 {
+		next = next.Add(time.Duration(rand.Int63n(int64(job.WindowMinutes) * int64(time.Minute))))
+	}
+	return next
+}
+
+func (s *Scheduler) runJob(ctx context.Context, job config.Job) error {
+	keys, err := s.jobKeys(job)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	pending := make([]string, 0, len(keys))
+	for _, key := range keys {
+		address, _ := keyAddress(key) // validated by jobKeys
+		if s.state.ranToday(job.Name, address, now) {
+			continue
+		}
+		pending = append(pending, key)
+	}
+
+	if len(pending) == 0 {
+		logger.GlobalLogger.Infof("[SCHEDULER] Job %s: all accounts already ran today", job.Name)
+		return nil
+	}
+
+	logger.GlobalLogger.Infof("[SCHEDULER] Job %s: starting for %d accounts (%d skipped)", job.Name, len(pending), len(keys)-len(pending))
+	completed, runErr := s.run(ctx, job, pending)
+
+	if err := s.state.markRun(job.Name, completed, time.Now().UTC()); err != nil {
+		logger.GlobalLogger.Warnf("[SCHEDULER] Job %s: %v", job.Name, err)
+	}
+
+	return runErr
+}
+
+func (s *Scheduler) jobKeys(job config.Job) ([]string, error) {
+	if job.Group == "" {
+		return s.defaultKeys, nil
+	}
+
+	lines, err := utils.FileReader(s.schedule.Groups[job.Group])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read group %s: %w", job.Group, err)
+	}
+
+	keys := make([]string, 0, len(lines))
+	for i, line := range lines {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		if _, err := keyAddress(line); err != nil {
+			return nil, fmt.Errorf("group %s, line %d: %w", job.Group, i+1, err)
+		}
+		keys = append(keys, line)
+	}
+	return keys, nil
+}
+
+// keyAddress returns the checksummed account address for a line of a group:
+// a private key or an address in any case.
+func keyAddress(key string) (string, error) {
+	if common.IsHexAddress(key) {
+		return common.HexToAddress(key).Hex(), nil
+	}
+
+	privateKey, err := utils.ParsePrivateKey(key)
+	if err != nil {
+		return "", fmt.Errorf("not a private key or an address")
+	}
+	addr, err := utils.DeriveAddress(privateKey)
+	if err != nil {
+		return "", err
+	}
+	return addr.Hex(), nil
+}
@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const dayLayout = "2006-01-02"
+
+type jobState struct {
+	LastRun  time.Time         `json:"last_run"`
+	Accounts map[string]string `json:"accounts"` // address -> day of the last run
+}
+
+type runState struct {
+	path string
+	mu   sync.Mutex
+	Jobs map[string]*jobState `json:"jobs"`
+}
+
+func loadRunState(path string) (*runState, error) {
+	state := &runState{path: path, Jobs: make(map[string]*jobState)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("failed to read schedule state: %w", err)
+	}
+
+	if len(data) == 0 {
+		return state, nil
+	}
+
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse schedule state: %w", err)
+	}
+	if state.Jobs == nil {
+		state.Jobs = make(map[string]*jobState)
+	}
+
+	return state, nil
+}
+
+func (s *runState) ranToday(job, address string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	js, exists := s.Jobs[job]
+	if !exists {
+		return false
+	}
+	return js.Accounts[address] == now.Format(dayLayout)
+}
+
+func (s *runState) markRun(job string, addresses []string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	js, exists := s.Jobs[job]
+	if !exists {
+		js = &jobState{}
+		s.Jobs[job] = js
+	}
+	if js.Accounts == nil {
+		js.Accounts = make(map[string]string)
+	}
+
+	js.LastRun = now
+	for _, addr := range addresses {
+		js.Accounts[addr] = now.Format(dayLayout)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize schedule state: %w", err)
+	}
+
+	tempPath := s.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write schedule state: %w", err)
+	}
+	if err := os.Rename(tempPath, s.path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write schedule state: %w", err)
+	}
+
+	return nil
+}
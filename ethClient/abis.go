@@ -0,0 +1,77 @@
+package ethClient
+
+import (
+	"fmt"
+	"lisk/globals"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+var (
+	abisMu     sync.RWMutex
+	knownABIs  = make(map[string]*abi.ABI)
+	knownOrder []string
+)
+
+// RegisterABIs makes module ABIs available for decoding calldata in logs.
+func RegisterABIs(abis map[string]*abi.ABI) {
+	abisMu.Lock()
+	defer abisMu.Unlock()
+
+	for name, parsed := range abis {
+		if _, exists := knownABIs[name]; !exists {
+			knownOrder = append(knownOrder, name)
+		}
+		knownABIs[name] = parsed
+	}
+}
+
+// registeredABIs returns the module ABIs followed by the ERC-20 ABI.
+func registeredABIs() []*abi.ABI {
+	abisMu.RLock()
+	defer abisMu.RUnlock()
+
+	result := make([]*abi.ABI, 0, len(knownOrder)+1)
+	for _, name := range knownOrder {
+		result = append(result, knownABIs[name])
+	}
+	return append(result, globals.Erc20ABI)
+}
+
+// decodeCall returns a human readable form of the calldata, e.g.
+// "approve(0x..., 1000)". Unknown selectors are returned as hex.
+func decodeCall(data []byte) string {
+	if len(data) == 0 {
+		return "transfer"
+	}
+	if len(data) < 4 {
+		return fmt.Sprintf("0x%x", data)
+	}
+
+	for _, parsed := range registeredABIs() {
+		method, err := parsed.MethodById(data[:4])
+		if err != nil {
+			continue
+		}
+
+		args, err := method.Inputs.Unpack(data[4:])
+		if err != nil {
+			return method.Name + "(?)"
+		}
+
+		parts := make([]string, 0, len(args))
+		for _, arg := range args {
+			switch v := arg.(type) {
+			case []byte:
+				parts = append(parts, fmt.Sprintf("0x%x", v))
+			default:
+				parts = append(parts, fmt.Sprintf("%v", v))
+			}
+		}
+		return method.Name + "(" + strings.Join(parts, ", ") + ")"
+	}
+
+	return fmt.Sprintf("0x%x", data[:4])
+}
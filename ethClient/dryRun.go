@@ -0,0 +1,145 @@
+package ethClient
+
+import (
+	"context"
+	"fmt"
+	"lisk/account"
+	"lisk/logger"
+	"lisk/utils"
+	"math/big"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type DryRunEntry struct {
+	Chain  string
+	From   common.Address
+	To     common.Address
+	Nonce  uint64
+	Value  *big.Int
+	Call   string
+	Gas    uint64
+	Cost   *big.Int
+	Result string
+}
+
+var (
+	dryRunMu      sync.Mutex
+	dryRunEntries []DryRunEntry
+)
+
+// simulateTransaction is the dry-run replacement of a broadcast: the
+// transaction is built and signed, then checked with estimateGas and eth_call
+// against the pending state.
+func (c *Client) simulateTransaction(ctx context.Context, chainID *big.Int, signer account.Signer, CA common.Address, nonce uint64, value *big.Int, txData []byte) error {
+	ownerAddr := signer.Address()
+	entry := DryRunEntry{
+		Chain: c.Chain,
+		From:  ownerAddr,
+		To:    CA,
+		Nonce: nonce,
+		Value: value,
+		Call:  decodeCall(txData),
+		Cost:  big.NewInt(0),
+	}
+
+	msg := ethereum.CallMsg{
+		From:  ownerAddr,
+		To:    &CA,
+		Value: value,
+		Data:  txData,
+	}
+
+	header, err := c.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("dry-run: failed to get header: %w", err)
+	}
+
+	tipCap, err := c.Client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return fmt.Errorf("dry-run: failed to get gas tip cap: %w", err)
+	}
+	feeCap := new(big.Int).Add(header.BaseFee, tipCap)
+
+	entry.Result = "ok"
+	if gas, err := c.Client.EstimateGas(ctx, msg); err != nil {
+		entry.Result = "estimate failed: " + asRevertError(err).Error()
+	} else {
+		entry.Gas = gas
+		entry.Cost = new(big.Int).Mul(new(big.Int).SetUint64(gas), feeCap)
+	}
+
+	if _, err := c.Client.PendingCallContract(ctx, msg); err != nil && entry.Result == "ok" {
+		entry.Result = "eth_call failed: " + asRevertError(err).Error()
+	}
+
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     nonce,
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       entry.Gas,
+		To:        &CA,
+		Value:     value,
+		Data:      txData,
+	})
+
+	if l1Fee, err := c.L1Fee(ctx, tx); err != nil {
+		logger.GlobalLogger.Warnf("[DRY-RUN] Failed to get L1 fee: %v", err)
+	} else {
+		entry.Cost.Add(entry.Cost, l1Fee)
+	}
+
+	signedTx, err := signer.SignTx(ctx, tx, chainID)
+	if err != nil {
+		return fmt.Errorf("dry-run: failed to sign transaction: %w", err)
+	}
+
+	logger.GlobalLogger.Infof("[DRY-RUN][%s][NONCE: %v] %s -> %s %s value=%s gas=%d cost=%s ETH hash=%s: %s",
+		c.Chain, nonce, ownerAddr.Hex(), CA.Hex(), entry.Call, value, entry.Gas,
+		utils.ConvertFromWei(entry.Cost, 18), signedTx.Hash().Hex(), entry.Result)
+
+	dryRunMu.Lock()
+	dryRunEntries = append(dryRunEntries, entry)
+	dryRunMu.Unlock()
+
+	return nil
+}
+
+// PrintDryRunSummary logs a table of all simulated transactions.
+func PrintDryRunSummary() {
+	dryRunMu.Lock()
+	defer dryRunMu.Unlock()
+
+	if len(dryRunEntries) == 0 {
+		logger.GlobalLogger.Infof("[DRY-RUN] No transactions were simulated")
+		return
+	}
+
+	var (
+		sb    strings.Builder
+		total = big.NewInt(0)
+	)
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHAIN\tFROM\tTO\tCALL\tGAS\tCOST ETH\tRESULT")
+	for _, e := range dryRunEntries {
+		call := e.Call
+		if len(call) > 60 {
+			call = call[:57] + "..."
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n", e.Chain, e.From.Hex(), e.To.Hex(), call, e.Gas, utils.ConvertFromWei(e.Cost, 18), e.Result)
+		total.Add(total, e.Cost)
+	}
+	w.Flush()
+
+	logger.GlobalLogger.Infof("[DRY-RUN] Summary: %d transactions, estimated cost %s ETH", len(dryRunEntries), utils.ConvertFromWei(total, 18))
+	for _, line := range strings.Split(strings.TrimRight(sb.String(), "\n"), "\n") {
+		logger.GlobalLogger.Infof("%s", line)
+	}
+}
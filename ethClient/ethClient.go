@@ -2,12 +2,13 @@ package ethClient
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"lisk/account"
+	"lisk/config"
 	"lisk/globals"
 	"lisk/logger"
+	"lisk/utils"
 	"math/big"
 	"strings"
 	"sync"
@@ -16,45 +17,63 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/crypto"
 	"golang.org/x/sync/errgroup"
 )
 
+const requiredChain = "lisk"
+
 type Client struct {
-	Client *ethclient.Client
+	Client *Pool
+	Chain  string
+	Nonces *NonceManager
+	l1     l1Oracle
+}
+
+// TxPendingError is returned when a broadcast transaction was not mined in
+// time. The transaction may still be included later, so the action must not be
+// repeated blindly.
+type TxPendingError struct {
+	Chain string
+	Hash  common.Hash
 }
 
-func EthClientFactory(rpcs map[string]string) (map[string]*Client, error) {
+func (e *TxPendingError) Error() string {
+	return fmt.Sprintf("transaction wait timeout: %s on %s is still pending", e.Hash.Hex(), e.Chain)
+}
+
+var errTxFailed = errors.New("transaction failed")
+
+// EthClientFactory builds a client pool for every configured chain. A chain
+// whose endpoints are all unavailable is skipped with a warning, only lisk is
+// required to start.
+func EthClientFactory(rpcs map[string]config.RPCEndpoints) (map[string]*Client, error) {
 	if len(rpcs) == 0 {
 		return nil, errors.New("RPC URLs map is empty")
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	g, ctx := errgroup.WithContext(ctx)
-
 	var (
+		g      errgroup.Group
 		result = make(map[string]*Client)
 		mu     sync.Mutex
 	)
 
-	for name, rpc := range rpcs {
+	for name, endpoints := range rpcs {
 		name := name
-		rpc := rpc
+		endpoints := endpoints
 
 		g.Go(func() error {
-			if ctx.Err() != nil {
-				return ctx.Err()
-			}
-
-			client, err := ethclient.DialContext(ctx, rpc)
+			pool, err := NewPool(context.Background(), name, endpoints)
 			if err != nil {
-				return err
+				if name == requiredChain {
+					return err
+				}
+				logger.GlobalLogger.Warnf("[RPC] Chain %s is disabled: %v", name, err)
+				return nil
 			}
 
 			mu.Lock()
-			result[name] = &Client{Client: client}
+			result[name] = &Client{Client: pool, Chain: name, Nonces: NewNonceManager(pool, name)}
 			mu.Unlock()
 
 			return nil
@@ -62,9 +81,14 @@ func EthClientFactory(rpcs map[string]string) (map[string]*Client, error) {
 	}
 
 	if err := g.Wait(); err != nil {
+		CloseAllClients(result)
 		return nil, err
 	}
 
+	if _, ok := result[requiredChain]; !ok {
+		return nil, fmt.Errorf("RPC for %s is not configured", requiredChain)
+	}
+
 	return result, nil
 }
 
@@ -76,109 +100,222 @@ func CloseAllClients(clients map[string]*Client) {
 	}
 }
 
-func (c *Client) BalanceCheck(owner, tokenAddr common.Address) (*big.Int, error) {
+func (c *Client) BalanceCheck(ctx context.Context, owner, tokenAddr common.Address) (*big.Int, error) {
 	if IsNativeToken(tokenAddr) {
-		balance, err := c.Client.BalanceAt(context.Background(), owner, nil)
+		balance, err := c.Client.BalanceAt(ctx, owner, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get native coin balance: %v", err)
+			return nil, fmt.Errorf("failed to get native coin balance: %w", err)
 		}
 		return balance, nil
 	}
 
 	data, err := globals.Erc20ABI.Pack("balanceOf", owner)
 	if err != nil {
-		return nil, fmt.Errorf("failed to pack data: %v", err)
+		return nil, fmt.Errorf("failed to pack data: %w", err)
 	}
 
-	result, err := c.CallCA(tokenAddr, data)
+	result, err := c.CallCA(ctx, tokenAddr, data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call contract: %v", err)
+		return nil, fmt.Errorf("failed to call contract: %w", err)
 	}
 
 	var balance *big.Int
 	if err := globals.Erc20ABI.UnpackIntoInterface(&balance, "balanceOf", result); err != nil {
-		return nil, fmt.Errorf("failed to unpack result: %v", err)
+		return nil, fmt.Errorf("failed to unpack result: %w", err)
 	}
 
 	return balance, nil
 }
 
-func (c *Client) CallCA(toCA common.Address, data []byte) ([]byte, error) {
+func (c *Client) CallCA(ctx context.Context, toCA common.Address, data []byte) ([]byte, error) {
 	callMsg := ethereum.CallMsg{
 		To:   &toCA,
 		Data: data,
 	}
 
-	return c.Client.CallContract(context.Background(), callMsg, nil)
+	result, err := c.Client.CallContract(ctx, callMsg, nil)
+	if err != nil {
+		return nil, asRevertError(err)
+	}
+	return result, nil
 }
 
-func (c *Client) GetGasValues(msg ethereum.CallMsg) (uint64, *big.Int, *big.Int, error) {
+// GetGasValues estimates the fees of msg and waits while they are above the
+// limits: AttentionGwei for the fee cap and AttentionTxCost for the total cost
+// of the transaction including the L1 data fee.
+func (c *Client) GetGasValues(ctx context.Context, msg ethereum.CallMsg) (*GasValues, error) {
 	timeout := time.After(time.Duration(globals.MaxAttentionTime) * time.Minute)
 	ticker := time.NewTicker(time.Second * time.Duration(globals.AttentionTime))
 	defer ticker.Stop()
 
+	chainID, err := c.Client.NetworkID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ChainID: %w", err)
+	}
+
 	for {
 		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
 		case <-timeout:
 			logger.GlobalLogger.Errorf("Gas wait timeout has been exceeded. Cycle interrupted.")
-			return 0, big.NewInt(0), big.NewInt(0), fmt.Errorf("Gas wait timeout has been exceeded. Cycle interrupted.")
+			return nil, fmt.Errorf("%w after %d minutes", globals.ErrGasTimeout, globals.MaxAttentionTime)
 
 		case <-ticker.C:
-			header, err := c.Client.HeaderByNumber(context.Background(), nil)
+			header, err := c.Client.HeaderByNumber(ctx, nil)
 			if err != nil {
 				logger.GlobalLogger.Errorf("Ошибка получения заголовка блока: %v", err)
-				return 0, nil, nil, fmt.Errorf("Ошибка получения заголовка блока: %w", err)
+				return nil, fmt.Errorf("Ошибка получения заголовка блока: %w", err)
 			}
 
-			maxPriorityFeePerGas, err := c.Client.SuggestGasTipCap(context.Background())
+			maxPriorityFeePerGas, err := c.Client.SuggestGasTipCap(ctx)
 			if err != nil {
 				logger.GlobalLogger.Errorf("Ошибка получения предложения Gas Tip Cap: %v", err)
-				return 0, nil, nil, fmt.Errorf("Ошибка получения предложения Gas Tip Cap: %w", err)
+				return nil, fmt.Errorf("Ошибка получения предложения Gas Tip Cap: %w", err)
 			}
 
 			maxFeePerGas := new(big.Int).Add(header.BaseFee, maxPriorityFeePerGas)
 
-			gasLimit, err := c.Client.EstimateGas(context.Background(), msg)
+			gasLimit, err := c.Client.EstimateGas(ctx, msg)
 			if err != nil {
+				err = classifyNodeError(asRevertError(err))
 				logger.GlobalLogger.Errorf("Ошибка оценки газа: %v", err)
-				return 0, nil, nil, fmt.Errorf("Ошибка оценки газа: %w", err)
+				return nil, fmt.Errorf("Ошибка оценки газа: %w", err)
+			}
+
+			// The nonce is not known here, it changes the encoded size by a byte or two.
+			l1Fee, err := c.L1Fee(ctx, types.NewTx(&types.DynamicFeeTx{
+				ChainID:   chainID,
+				GasTipCap: maxPriorityFeePerGas,
+				GasFeeCap: maxFeePerGas,
+				Gas:       gasLimit,
+				To:        msg.To,
+				Value:     msg.Value,
+				Data:      msg.Data,
+			}))
+			if err != nil {
+				logger.GlobalLogger.Errorf("Failed to get L1 fee: %v", err)
+				return nil, fmt.Errorf("failed to get L1 fee: %w", err)
+			}
+
+			values := &GasValues{
+				GasLimit: gasLimit,
+				TipCap:   maxPriorityFeePerGas,
+				FeeCap:   maxFeePerGas,
+				L1Fee:    l1Fee,
 			}
 
 			if maxFeePerGas.Cmp(globals.AttentionGwei) > 0 {
-				logger.GlobalLogger.Warnf("[ATTENTION] High gwei %v", maxFeePerGas)
+				logger.GlobalLogger.Warnf("[ATTENTION] High gwei %v, tx cost %s ETH", maxFeePerGas, formatCost(values))
 				continue
-			} else {
-				return gasLimit, maxPriorityFeePerGas, maxFeePerGas, nil
 			}
+			if globals.AttentionTxCost != nil && values.Cost().Cmp(globals.AttentionTxCost) > 0 {
+				logger.GlobalLogger.Warnf("[ATTENTION] High tx cost %s ETH, limit %s ETH", formatCost(values), utils.ConvertFromWei(globals.AttentionTxCost, 18))
+				continue
+			}
+
+			return values, nil
 		}
 	}
 }
 
-func (c *Client) GetNonce(address common.Address) uint64 {
-	nonce, err := c.Client.PendingNonceAt(context.Background(), address)
+// The calldata of an action is not known before it is generated, so the cost
+// check of a gas budget assumes a swap through the universal router.
+const (
+	actionGasEstimate      = 350000
+	actionCalldataEstimate = 600
+)
+
+// EstimateActionCost returns the maximum fee of a typical action at the
+// current gas prices, L1 data fee included.
+func (c *Client) EstimateActionCost(ctx context.Context) (*big.Int, error) {
+	chainID, err := c.Client.NetworkID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ChainID: %w", err)
+	}
+
+	header, err := c.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header: %w", err)
+	}
+
+	tipCap, err := c.Client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas tip cap: %w", err)
+	}
+
+	// Hash chains do not compress, so the L1 fee of real calldata is not
+	// underestimated.
+	data := make([]byte, 0, actionCalldataEstimate+common.HashLength)
+	for seed := (common.Hash{}); len(data) < actionCalldataEstimate; {
+		seed = crypto.Keccak256Hash(seed[:])
+		data = append(data, seed[:]...)
+	}
+
+	gas := &GasValues{
+		GasLimit: actionGasEstimate,
+		TipCap:   tipCap,
+		FeeCap:   new(big.Int).Add(header.BaseFee, tipCap),
+	}
+
+	gas.L1Fee, err = c.L1Fee(ctx, types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		GasTipCap: gas.TipCap,
+		GasFeeCap: gas.FeeCap,
+		Gas:       gas.GasLimit,
+		To:        &Multicall3,
+		Data:      data[:actionCalldataEstimate],
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L1 fee: %w", err)
+	}
+
+	return gas.Cost(), nil
+}
+
+// GasPrice returns the price per gas a transaction sent now pays: the base fee
+// of the latest block plus the suggested tip.
+func (c *Client) GasPrice(ctx context.Context) (*big.Int, error) {
+	header, err := c.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header: %w", err)
+	}
+
+	tipCap, err := c.Client.SuggestGasTipCap(ctx)
 	if err != nil {
-		logger.GlobalLogger.Warnf("Failed to get nonce for address %s: %v", address.Hex(), err)
-		return 0
+		return nil, fmt.Errorf("failed to get gas tip cap: %w", err)
 	}
-	return nonce
+
+	return new(big.Int).Add(header.BaseFee, tipCap), nil
 }
 
-func (c *Client) GetChainID() (int64, error) {
-	chainID, err := c.Client.NetworkID(context.Background())
+// GetNonce returns the pending nonce reported by the node. Transactions sent
+// through SendTransaction take their nonce from the local NonceManager instead.
+func (c *Client) GetNonce(ctx context.Context, address common.Address) (uint64, error) {
+	nonce, err := c.Client.PendingNonceAt(ctx, address)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get nonce for address %s: %w", address.Hex(), err)
+	}
+	return nonce, nil
+}
+
+func (c *Client) GetChainID(ctx context.Context) (int64, error) {
+	chainID, err := c.Client.NetworkID(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get ChainID: %w", err)
 	}
 	return chainID.Int64(), nil
 }
 
-func (c *Client) ApproveTx(tokenAddr, spender common.Address, acc *account.Account, amount *big.Int, rollback bool) (*types.Transaction, error) {
+func (c *Client) ApproveTx(ctx context.Context, tokenAddr, spender common.Address, acc *account.Account, amount *big.Int, rollback bool) (*types.Transaction, error) {
 	if IsNativeToken(tokenAddr) {
 		return nil, nil
 	}
 
-	allowance, err := c.Allowance(tokenAddr, acc.Address, spender)
+	allowance, err := c.Allowance(ctx, tokenAddr, acc.Address, spender)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get allowance: %v", err)
+		return nil, fmt.Errorf("failed to get allowance: %w", err)
 	}
 
 	var approveValue *big.Int
@@ -193,22 +330,21 @@ func (c *Client) ApproveTx(tokenAddr, spender common.Address, acc *account.Accou
 
 	approveData, err := globals.Erc20ABI.Pack("approve", spender, approveValue)
 	if err != nil {
-		return nil, fmt.Errorf("failed to pack approve data: %v", err)
+		return nil, fmt.Errorf("failed to pack approve data: %w", err)
 	}
 
 	logger.GlobalLogger.Infof("Approve transaction...")
-	if err := c.SendTransaction(acc.PrivateKey, acc.Address, tokenAddr, c.GetNonce(acc.Address), big.NewInt(0), approveData); err != nil {
+	if err := c.SendTransaction(WithApproval(ctx), acc.Signer, tokenAddr, big.NewInt(0), approveData); err != nil {
 		return nil, err
 	}
 
-	time.Sleep(time.Second * 15)
 	return nil, nil
 }
 
-func (c *Client) Allowance(tokenAddr, owner, spender common.Address) (*big.Int, error) {
+func (c *Client) Allowance(ctx context.Context, tokenAddr, owner, spender common.Address) (*big.Int, error) {
 	data, err := globals.Erc20ABI.Pack("allowance", owner, spender)
 	if err != nil {
-		return nil, fmt.Errorf("failed to pack allowance data: %v", err)
+		return nil, fmt.Errorf("failed to pack allowance data: %w", err)
 	}
 
 	msg := ethereum.CallMsg{
@@ -216,106 +352,182 @@ func (c *Client) Allowance(tokenAddr, owner, spender common.Address) (*big.Int,
 		Data: data,
 	}
 
-	result, err := c.Client.CallContract(context.Background(), msg, nil)
+	result, err := c.Client.CallContract(ctx, msg, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call contract: %v", err)
+		return nil, fmt.Errorf("failed to call contract: %w", asRevertError(err))
 	}
 
 	var allowance *big.Int
 	if err = globals.Erc20ABI.UnpackIntoInterface(&allowance, "allowance", result); err != nil {
-		return nil, fmt.Errorf("failed to unpack allowance data: %v", err)
+		return nil, fmt.Errorf("failed to unpack allowance data: %w", err)
 	}
 
 	return allowance, nil
 }
 
-func (c *Client) SendTransaction(privateKey *ecdsa.PrivateKey, ownerAddr, CA common.Address, nonce uint64, value *big.Int, txData []byte) error {
-	chainID, err := c.Client.NetworkID(context.Background())
+// SendTransaction signs and broadcasts a transaction with the next local nonce
+// of ownerAddr and waits for it to be mined.
+func (c *Client) SendTransaction(ctx context.Context, signer account.Signer, CA common.Address, value *big.Int, txData []byte) error {
+	ownerAddr := signer.Address()
+
+	chainID, err := c.Client.NetworkID(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get ChainID: %v", err)
+		return fmt.Errorf("failed to get ChainID: %w", err)
 	}
 
-	gasLimit, maxPriorityFeePerGas, maxFeePerGas, err := c.GetGasValues(ethereum.CallMsg{
+	reservation, err := c.Nonces.Reserve(ctx, ownerAddr)
+	if err != nil {
+		return err
+	}
+	outcome := NonceUnused
+	defer func() { reservation.Release(outcome) }()
+
+	nonce := reservation.Nonce
+
+	if globals.DryRun {
+		return c.simulateTransaction(ctx, chainID, signer, CA, nonce, value, txData)
+	}
+
+	gas, err := c.GetGasValues(ctx, ethereum.CallMsg{
 		From:  ownerAddr,
 		To:    &CA,
 		Value: value,
 		Data:  txData,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to estimate gas: %v", err)
+		return fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	if checkCost := txMetaFrom(ctx).CheckCost; checkCost != nil {
+		if err := checkCost(gas.Cost()); err != nil {
+			return err
+		}
 	}
 
 	dynamicTx := types.DynamicFeeTx{
 		ChainID:   chainID,
 		Nonce:     nonce,
-		GasTipCap: maxPriorityFeePerGas,
-		GasFeeCap: maxFeePerGas,
-		Gas:       gasLimit,
+		GasTipCap: gas.TipCap,
+		GasFeeCap: gas.FeeCap,
+		Gas:       gas.GasLimit,
 		To:        &CA,
 		Value:     value,
 		Data:      txData,
 	}
 
-	signedTx, err := types.SignTx(types.NewTx(&dynamicTx), types.LatestSignerForChainID(chainID), privateKey)
+	signedTx, err := signer.SignTx(ctx, types.NewTx(&dynamicTx), chainID)
 	if err != nil {
-		return fmt.Errorf("failed to sign transaction: %v", err)
+		return fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
-	if err = c.Client.SendTransaction(context.Background(), signedTx); err != nil {
-		return fmt.Errorf("failed to send transaction: %v", err)
+	if err = c.Client.SendTransaction(ctx, signedTx); err != nil {
+		if !isAlreadyKnown(err) {
+			err = classifyNodeError(err)
+			if errors.Is(err, globals.ErrNonceTooLow) || isNonceError(err) {
+				outcome = NonceInvalid
+			}
+			return fmt.Errorf("failed to send transaction: %w", err)
+		}
+		logger.GlobalLogger.Infof("[NONCE: %v] Transaction %s is already known to the node", nonce, signedTx.Hash().Hex())
 	}
+	outcome = NoncePending
+	c.journalSent(ctx, chainID, signedTx, gas.L1Fee, common.Hash{})
+
+	logger.GlobalLogger.Infof("[NONCE: %v] Transaction sent, max cost %s ETH: https://blockscout.lisk.com/tx/%s", nonce, formatCost(gas), signedTx.Hash().Hex())
 
-	logger.GlobalLogger.Infof("[NONCE: %v] Transaction sent: https://blockscout.lisk.com/tx/%s", nonce, signedTx.Hash().Hex())
+	// A broadcast transaction is waited for even after shutdown was requested,
+	// so its outcome is known before the state is saved.
+	_, err = c.waitWithReplacement(context.WithoutCancel(ctx), chainID, signer, signedTx)
 
-	return c.waitForTransactionSuccess(signedTx.Hash(), 1*time.Minute)
+	var cancelled *TxCancelledError
+	if err == nil || errors.Is(err, errTxFailed) || errors.As(err, &cancelled) {
+		outcome = NonceMined
+	}
+	return err
 }
 
-func (c *Client) waitForTransactionSuccess(txHash common.Hash, timeout time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+// WaitForTransaction waits for a previously sent transaction, e.g. one that
+// was recorded as pending before a restart. Replacements of it known to the
+// journal are watched as well.
+func (c *Client) WaitForTransaction(ctx context.Context, txHash common.Hash, timeout time.Duration) error {
+	return c.waitForTransactionSuccess(ctx, txHash, timeout)
+}
+
+func (c *Client) waitForTransactionSuccess(ctx context.Context, txHash common.Hash, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	ticker := time.NewTicker(3 * time.Second)
 	defer ticker.Stop()
 
+	versions := TxJournal.Versions(txHash)
+
 	for {
 		select {
 		case <-ctx.Done():
-			return fmt.Errorf("transaction wait timeout")
+			return &TxPendingError{Chain: c.Chain, Hash: txHash}
 		case <-ticker.C:
-			receipt, err := c.Client.TransactionReceipt(context.Background(), txHash)
-			if err != nil {
-				if isUnknownBlockError(err) {
+			// A missing receipt, a lagging node or a failing endpoint are all
+			// retried until the timeout.
+			for _, hash := range versions {
+				receipt, err := c.Client.TransactionReceipt(ctx, hash)
+				if err != nil {
 					continue
 				}
-				return fmt.Errorf("error getting transaction receipt: %v", err)
-			}
 
-			if receipt.Status == 1 {
-				return nil
-			} else {
-				return fmt.Errorf("transaction failed")
+				if entry, exists := TxJournal.Entry(hash); exists && entry.Action == TxActionCancel {
+					c.recordReceipt(ctx, receipt, TxStatusCancelled)
+					return &TxCancelledError{Chain: c.Chain, Hash: hash}
+				}
+				if receipt.Status == types.ReceiptStatusSuccessful {
+					c.recordReceipt(ctx, receipt, TxStatusSuccess)
+					return nil
+				}
+				c.recordReceipt(ctx, receipt, TxStatusFailed)
+				return errTxFailed
 			}
 		}
 	}
 }
 
-func isUnknownBlockError(err error) bool {
-	if err == nil {
-		return false
+// formatCost prints the total cost with its L2 and L1 parts.
+func formatCost(gas *GasValues) string {
+	if gas.L1Fee.Sign() == 0 {
+		return utils.ConvertFromWei(gas.Cost(), 18)
 	}
-	errMsg := err.Error()
+	return fmt.Sprintf("%s (L2 %s + L1 %s)", utils.ConvertFromWei(gas.Cost(), 18), utils.ConvertFromWei(gas.L2Cost(), 18), utils.ConvertFromWei(gas.L1Fee, 18))
+}
 
-	if strings.Contains(errMsg, "Unknown block") {
-		return true
+// classifyNodeError maps txpool errors of the node to the error kinds of
+// globals. Nodes report them only as text, so this is the one place that
+// looks at the message.
+func classifyNodeError(err error) error {
+	if err == nil {
+		return nil
 	}
-
-	if strings.Contains(errMsg, "You reached the free tier limits, please upgrade your tier to paid one") {
-		return true
+	errMsg := strings.ToLower(err.Error())
+
+	switch {
+	case strings.Contains(errMsg, "nonce too low"):
+		return fmt.Errorf("%w: %w", globals.ErrNonceTooLow, err)
+	case strings.Contains(errMsg, "insufficient funds"):
+		return fmt.Errorf("%w: %w", globals.ErrInsufficientBalance, err)
+	default:
+		return err
 	}
+}
 
-	if strings.Contains(errMsg, "not found") {
-		return true
-	}
+// isAlreadyKnown reports that the node already has this exact signed
+// transaction in its txpool, e.g. after a send was retried on another
+// endpoint. The transaction counts as sent.
+func isAlreadyKnown(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "already known")
+}
+
+// isNonceError reports other nonce rejections that need a resync.
+func isNonceError(err error) bool {
+	errMsg := strings.ToLower(err.Error())
 
-	return false
+	return strings.Contains(errMsg, "nonce too high") ||
+		strings.Contains(errMsg, "replacement transaction underpriced")
 }
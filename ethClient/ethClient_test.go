@@ -0,0 +1,65 @@
+package ethClient
+
+import (
+	"context"
+	"errors"
+	"lisk/account"
+	"lisk/globals"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func (stubChain) GetTransactionCount(address common.Address, block string) hexutil.Uint64 {
+	return 0
+}
+
+func TestSendTransactionGasTimeout(t *testing.T) {
+	oldAttention, oldMax, oldDryRun := globals.AttentionTime, globals.MaxAttentionTime, globals.DryRun
+	t.Cleanup(func() {
+		globals.AttentionTime, globals.MaxAttentionTime, globals.DryRun = oldAttention, oldMax, oldDryRun
+	})
+	// The gas wait times out before the first fee check.
+	globals.AttentionTime, globals.MaxAttentionTime, globals.DryRun = 60, 0, false
+
+	pool := newTestPool(t, newStubNode(t))
+	client := &Client{Client: pool, Chain: "test", Nonces: NewNonceManager(pool, "test")}
+
+	key, _ := crypto.GenerateKey()
+	err := client.SendTransaction(context.Background(), account.NewKeySigner(key), common.Address{}, big.NewInt(0), nil)
+	if !errors.Is(err, globals.ErrGasTimeout) {
+		t.Fatalf("SendTransaction error = %v, want ErrGasTimeout in the chain", err)
+	}
+}
+
+func TestClassifyNodeError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want error
+	}{
+		{errors.New("nonce too low: next nonce 5, tx nonce 4"), globals.ErrNonceTooLow},
+		{errors.New("insufficient funds for gas * price + value"), globals.ErrInsufficientBalance},
+	}
+	for _, tt := range tests {
+		if err := classifyNodeError(tt.err); !errors.Is(err, tt.want) {
+			t.Errorf("classifyNodeError(%q) = %v, want %v", tt.err, err, tt.want)
+		}
+	}
+
+	if err := classifyNodeError(errors.New("execution reverted")); errors.Is(err, globals.ErrNonceTooLow) || errors.Is(err, globals.ErrInsufficientBalance) {
+		t.Errorf("unrelated error was classified: %v", err)
+	}
+}
+
+func TestAlreadyKnownIsNotANonceError(t *testing.T) {
+	err := errors.New("already known")
+	if !isAlreadyKnown(err) {
+		t.Fatal("already known is not detected")
+	}
+	if errors.Is(classifyNodeError(err), globals.ErrNonceTooLow) || isNonceError(err) {
+		t.Fatal("already known is treated as a nonce error")
+	}
+}
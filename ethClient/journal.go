@@ -0,0 +1,448 @@
+package ethClient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lisk/logger"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxStatus is the state of a journal entry.
+type TxStatus string
+
+const (
+	TxStatusPending   TxStatus = "pending"
+	TxStatusSuccess   TxStatus = "success"
+	TxStatusFailed    TxStatus = "failed"
+	TxStatusReplaced  TxStatus = "replaced"  // another transaction with the same nonce was mined
+	TxStatusCancelled TxStatus = "cancelled" // the cancel transaction itself
+)
+
+// Actions of transactions that do not complete an action by themselves.
+const (
+	TxActionApprove = "Approve"
+	TxActionCancel  = "Cancel"
+)
+
+// JournalEntry describes one sent transaction. Amounts are in wei.
+type JournalEntry struct {
+	Account           string            `json:"account"`
+	Chain             string            `json:"chain"`
+	Module            string            `json:"module,omitempty"`
+	Action            string            `json:"action,omitempty"`
+	Key               string            `json:"key,omitempty"`
+	Details           map[string]string `json:"details,omitempty"` // e.g. the swap route and quote
+	Nonce             uint64            `json:"nonce"`
+	Hash              string            `json:"hash"`
+	Replaces          string            `json:"replaces,omitempty"`
+	To                string            `json:"to"`
+	Value             string            `json:"value"`
+	GasLimit          uint64            `json:"gas_limit"`
+	GasUsed           uint64            `json:"gas_used,omitempty"`
+	EffectiveGasPrice string            `json:"effective_gas_price,omitempty"`
+	L1Fee             string            `json:"l1_fee,omitempty"`
+	Status            TxStatus          `json:"status"`
+	Settled           bool              `json:"settled,omitempty"`
+	SentAt            time.Time         `json:"sent_at"`
+	MinedAt           *time.Time        `json:"mined_at,omitempty"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+// TxMeta tells the journal which action a transaction belongs to. Key
+// identifies the action in the state file, see WithTxMeta. CheckCost is called
+// with the estimated maximum cost before a transaction is sent and stops it
+// with its error. OnReceipt is called with the fee paid (L2 execution plus L1
+// data) of every mined transaction of the action. Details are written to the
+// journal as they are.
+type TxMeta struct {
+	Module    string
+	Action    string
+	Key       string
+	Details   map[string]string
+	CheckCost func(cost *big.Int) error
+	OnReceipt func(gasUsed uint64, fee *big.Int, failed bool)
+}
+
+type txMetaKey struct{}
+
+// WithTxMeta attaches the action description to the transactions sent with ctx.
+func WithTxMeta(ctx context.Context, meta TxMeta) context.Context {
+	return context.WithValue(ctx, txMetaKey{}, meta)
+}
+
+// WithApproval marks the transactions sent with ctx as approvals of the
+// current action.
+func WithApproval(ctx context.Context) context.Context {
+	return withTxAction(ctx, TxActionApprove)
+}
+
+// WithTxDetails adds details to the journal entries of the transactions sent
+// with ctx.
+func WithTxDetails(ctx context.Context, details map[string]string) context.Context {
+	meta := txMetaFrom(ctx)
+	merged := make(map[string]string, len(meta.Details)+len(details))
+	for key, value := range meta.Details {
+		merged[key] = value
+	}
+	for key, value := range details {
+		merged[key] = value
+	}
+	meta.Details = merged
+	return WithTxMeta(ctx, meta)
+}
+
+func withTxAction(ctx context.Context, action string) context.Context {
+	meta := txMetaFrom(ctx)
+	meta.Action = action
+	return WithTxMeta(ctx, meta)
+}
+
+func txMetaFrom(ctx context.Context) TxMeta {
+	meta, _ := ctx.Value(txMetaKey{}).(TxMeta)
+	return meta
+}
+
+// TxJournal is the journal of the current run, nil disables journaling.
+var TxJournal *Journal
+
+// Journal is an append-only JSONL file of sent transactions. Every change of
+// an entry appends its full new version, the last line of a hash wins.
+type Journal struct {
+	mu      sync.Mutex
+	file    *os.File
+	entries map[string]*JournalEntry
+}
+
+// OpenJournal loads the journal at path and opens it for appending.
+func OpenJournal(path string) (*Journal, error) {
+	j := &Journal{entries: make(map[string]*JournalEntry)}
+
+	if err := j.load(path); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	j.file = file
+
+	return j, nil
+}
+
+func (j *Journal) load(path string) error {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// A line cut off by a crash must not block the next run.
+			logger.GlobalLogger.Warnf("Journal %s: skipping broken line %d: %v", path, line, err)
+			continue
+		}
+		j.entries[entry.Hash] = &entry
+	}
+
+	return scanner.Err()
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.file.Close()
+}
+
+// write appends the new version of entry. Callers must hold j.mu.
+func (j *Journal) write(entry *JournalEntry) {
+	entry.UpdatedAt = time.Now().UTC()
+	j.entries[entry.Hash] = entry
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		logger.GlobalLogger.Warnf("Failed to encode journal entry %s: %v", entry.Hash, err)
+		return
+	}
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		logger.GlobalLogger.Warnf("Failed to write journal entry %s: %v", entry.Hash, err)
+	}
+}
+
+// Sent records a broadcast transaction as pending.
+func (j *Journal) Sent(entry JournalEntry) {
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry.Status = TxStatusPending
+	entry.SentAt = time.Now().UTC()
+	j.write(&entry)
+}
+
+// Mined records the receipt of hash. The other pending versions with the same
+// nonce are marked as replaced.
+func (j *Journal) Mined(hash common.Hash, receipt *types.Receipt, l1Fee *big.Int, status TxStatus) {
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	mined, exists := j.entries[hash.Hex()]
+	if !exists {
+		return
+	}
+
+	for _, entry := range j.entries {
+		if entry == mined || entry.Status != TxStatusPending || !sameNonce(entry, mined) {
+			continue
+		}
+		updated := *entry
+		updated.Status = TxStatusReplaced
+		j.write(&updated)
+	}
+
+	updated := *mined
+	updated.Status = status
+	updated.GasUsed = receipt.GasUsed
+	if receipt.EffectiveGasPrice != nil {
+		updated.EffectiveGasPrice = receipt.EffectiveGasPrice.String()
+	}
+	if l1Fee != nil {
+		updated.L1Fee = l1Fee.String()
+	}
+	minedAt := time.Now().UTC()
+	updated.MinedAt = &minedAt
+	j.write(&updated)
+}
+
+// Entry returns the last version of the entry of hash.
+func (j *Journal) Entry(hash common.Hash) (JournalEntry, bool) {
+	if j == nil {
+		return JournalEntry{}, false
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry, exists := j.entries[hash.Hex()]
+	if !exists {
+		return JournalEntry{}, false
+	}
+	return *entry, true
+}
+
+// Versions returns the hashes of all transactions sent with the same nonce as
+// hash, hash itself first.
+func (j *Journal) Versions(hash common.Hash) []common.Hash {
+	versions := []common.Hash{hash}
+	if j == nil {
+		return versions
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	original, exists := j.entries[hash.Hex()]
+	if !exists {
+		return versions
+	}
+
+	for _, entry := range j.entries {
+		if entry != original && sameNonce(entry, original) {
+			versions = append(versions, common.HexToHash(entry.Hash))
+		}
+	}
+	return versions
+}
+
+// Unsettled returns the transactions of the action key that were mined
+// successfully or are still pending but whose outcome is not in the state file
+// yet. Approvals and cancels are left out, they do not complete an action.
+func (j *Journal) Unsettled(account common.Address, key string) []JournalEntry {
+	if j == nil || key == "" {
+		return nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var result []JournalEntry
+	for _, entry := range j.entries {
+		if entry.Account != account.Hex() || entry.Key != key || entry.Settled || entry.Action == TxActionApprove || entry.Action == TxActionCancel {
+			continue
+		}
+		if entry.Status == TxStatusSuccess || entry.Status == TxStatusPending {
+			result = append(result, *entry)
+		}
+	}
+	return result
+}
+
+// Settle marks the transactions of the action key as accounted for in the
+// state file.
+func (j *Journal) Settle(account common.Address, key string) {
+	j.settle(account, func(entryKey string) bool { return entryKey == key })
+}
+
+// SettlePrefix marks the transactions of all actions whose key starts with
+// prefix as settled. The prefix must end with a delimiter of the key, so that
+// it does not match the keys of other actions that share its characters.
+func (j *Journal) SettlePrefix(account common.Address, prefix string) {
+	j.settle(account, func(entryKey string) bool { return strings.HasPrefix(entryKey, prefix) })
+}
+
+func (j *Journal) settle(account common.Address, match func(key string) bool) {
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, entry := range j.entries {
+		if entry.Account != account.Hex() || entry.Settled || !match(entry.Key) {
+			continue
+		}
+		updated := *entry
+		updated.Settled = true
+		j.write(&updated)
+	}
+}
+
+// SpentSince returns the fees of the transactions of account mined since the
+// given time.
+func (j *Journal) SpentSince(account common.Address, since time.Time) *big.Int {
+	spent := big.NewInt(0)
+	if j == nil {
+		return spent
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, entry := range j.entries {
+		if entry.Account != account.Hex() || entry.MinedAt == nil || entry.MinedAt.Before(since) {
+			continue
+		}
+		spent.Add(spent, entry.Fee())
+	}
+	return spent
+}
+
+// Fee is the fee paid by a mined transaction: gas used by the effective gas
+// price plus the L1 data fee.
+func (e *JournalEntry) Fee() *big.Int {
+	fee := new(big.Int).SetUint64(e.GasUsed)
+	if price, ok := new(big.Int).SetString(e.EffectiveGasPrice, 10); ok {
+		fee.Mul(fee, price)
+	} else {
+		fee.SetInt64(0)
+	}
+	if l1Fee, ok := new(big.Int).SetString(e.L1Fee, 10); ok {
+		fee.Add(fee, l1Fee)
+	}
+	return fee
+}
+
+func sameNonce(a, b *JournalEntry) bool {
+	return a.Account == b.Account && a.Chain == b.Chain && a.Nonce == b.Nonce
+}
+
+// journalSent records tx in TxJournal with the action of ctx.
+func (c *Client) journalSent(ctx context.Context, chainID *big.Int, tx *types.Transaction, l1Fee *big.Int, replaces common.Hash) {
+	if TxJournal == nil {
+		return
+	}
+
+	meta := txMetaFrom(ctx)
+	entry := JournalEntry{
+		Account:  c.senderOf(chainID, tx).Hex(),
+		Chain:    c.Chain,
+		Module:   meta.Module,
+		Action:   meta.Action,
+		Key:      meta.Key,
+		Details:  meta.Details,
+		Nonce:    tx.Nonce(),
+		Hash:     tx.Hash().Hex(),
+		Value:    tx.Value().String(),
+		GasLimit: tx.Gas(),
+	}
+	if tx.To() != nil {
+		entry.To = tx.To().Hex()
+	}
+	if l1Fee != nil {
+		entry.L1Fee = l1Fee.String()
+	}
+	if replaces != (common.Hash{}) {
+		entry.Replaces = replaces.Hex()
+	}
+
+	TxJournal.Sent(entry)
+}
+
+// recordReceipt records the receipt in the journal with the L1 fee reported by
+// the node and reports the fee paid to the action of ctx.
+func (c *Client) recordReceipt(ctx context.Context, receipt *types.Receipt, status TxStatus) {
+	l1Fee, err := c.Client.ReceiptL1Fee(ctx, receipt.TxHash)
+	if err != nil {
+		l1Fee = nil
+		if entry, exists := TxJournal.Entry(receipt.TxHash); exists {
+			// The estimate made before sending is better than nothing.
+			l1Fee, _ = new(big.Int).SetString(entry.L1Fee, 10)
+		}
+	}
+
+	TxJournal.Mined(receipt.TxHash, receipt, l1Fee, status)
+
+	if onReceipt := txMetaFrom(ctx).OnReceipt; onReceipt != nil {
+		fee := new(big.Int).SetUint64(receipt.GasUsed)
+		if receipt.EffectiveGasPrice != nil {
+			fee.Mul(fee, receipt.EffectiveGasPrice)
+		} else {
+			fee.SetInt64(0)
+		}
+		if l1Fee != nil {
+			fee.Add(fee, l1Fee)
+		}
+		onReceipt(receipt.GasUsed, fee, status != TxStatusSuccess)
+	}
+}
@@ -0,0 +1,129 @@
+package ethClient
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func openTestJournal(t *testing.T) (*Journal, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatalf("OpenJournal: %v", err)
+	}
+	t.Cleanup(func() { j.Close() })
+	return j, path
+}
+
+func sent(j *Journal, hash common.Hash, nonce uint64, key string) {
+	j.Sent(JournalEntry{Account: testAddress.Hex(), Chain: "test", Key: key, Nonce: nonce, Hash: hash.Hex()})
+}
+
+func TestJournalVersions(t *testing.T) {
+	j, _ := openTestJournal(t)
+	first, second, other := common.HexToHash("0x1"), common.HexToHash("0x2"), common.HexToHash("0x3")
+	sent(j, first, 4, "")
+	sent(j, second, 4, "")
+	sent(j, other, 5, "")
+
+	versions := j.Versions(first)
+	if len(versions) != 2 || versions[0] != first || versions[1] != second {
+		t.Fatalf("Versions = %v, want [%s %s]", versions, first, second)
+	}
+
+	unknown := common.HexToHash("0x9")
+	if versions := j.Versions(unknown); len(versions) != 1 || versions[0] != unknown {
+		t.Fatalf("Versions of an unknown hash = %v, want only the hash", versions)
+	}
+
+	var nilJournal *Journal
+	if versions := nilJournal.Versions(first); len(versions) != 1 {
+		t.Fatalf("Versions without a journal = %v", versions)
+	}
+}
+
+func TestJournalMinedReplacesOtherVersions(t *testing.T) {
+	j, _ := openTestJournal(t)
+	first, second := common.HexToHash("0x1"), common.HexToHash("0x2")
+	sent(j, first, 4, "")
+	sent(j, second, 4, "")
+
+	receipt := &types.Receipt{GasUsed: 21000, EffectiveGasPrice: big.NewInt(2)}
+	j.Mined(second, receipt, big.NewInt(100), TxStatusSuccess)
+
+	if entry, _ := j.Entry(first); entry.Status != TxStatusReplaced {
+		t.Fatalf("status of the replaced version = %s", entry.Status)
+	}
+	entry, _ := j.Entry(second)
+	if entry.Status != TxStatusSuccess || entry.Fee().Cmp(big.NewInt(42100)) != 0 {
+		t.Fatalf("mined entry = %s with fee %v, want success with fee 42100", entry.Status, entry.Fee())
+	}
+}
+
+func TestJournalSettleExactKey(t *testing.T) {
+	j, _ := openTestJournal(t)
+	sent(j, common.HexToHash("0x1"), 1, "state.json#0/1")
+	sent(j, common.HexToHash("0x2"), 2, "state.json#0/10")
+	sent(j, common.HexToHash("0x3"), 3, "state.json#0/11")
+
+	j.Settle(testAddress, "state.json#0/1")
+
+	if entries := j.Unsettled(testAddress, "state.json#0/1"); len(entries) != 0 {
+		t.Fatalf("settled action still has %d entries", len(entries))
+	}
+	for _, key := range []string{"state.json#0/10", "state.json#0/11"} {
+		if entries := j.Unsettled(testAddress, key); len(entries) != 1 {
+			t.Fatalf("settling state.json#0/1 settled %s", key)
+		}
+	}
+}
+
+func TestJournalSettlePrefix(t *testing.T) {
+	j, _ := openTestJournal(t)
+	sent(j, common.HexToHash("0x1"), 1, "state.json#0/1")
+	sent(j, common.HexToHash("0x2"), 2, "state.json#2/0")
+	sent(j, common.HexToHash("0x3"), 3, "state.json.bak#0/1")
+
+	j.SettlePrefix(testAddress, "state.json#")
+
+	if len(j.Unsettled(testAddress, "state.json#0/1")) != 0 || len(j.Unsettled(testAddress, "state.json#2/0")) != 0 {
+		t.Fatal("actions of the state file are not settled")
+	}
+	if len(j.Unsettled(testAddress, "state.json.bak#0/1")) != 1 {
+		t.Fatal("action of another state file was settled")
+	}
+}
+
+func TestJournalReload(t *testing.T) {
+	j, path := openTestJournal(t)
+	hash := common.HexToHash("0x1")
+	sent(j, hash, 1, "state.json#0/0")
+	j.Settle(testAddress, "state.json#0/0")
+	j.Close()
+
+	// A line cut off by a crash is skipped.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"account":"0x`)
+	file.Close()
+
+	reloaded, err := OpenJournal(path)
+	if err != nil {
+		t.Fatalf("OpenJournal: %v", err)
+	}
+	defer reloaded.Close()
+
+	entry, ok := reloaded.Entry(hash)
+	if !ok || !entry.Settled || entry.Status != TxStatusPending {
+		t.Fatalf("reloaded entry = %+v, want the last version", entry)
+	}
+}
@@ -0,0 +1,133 @@
+package ethClient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"lisk/logger"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// GasPriceOracle is the OP-Stack predeploy that prices the L1 data of a
+// transaction. On Lisk this fee is usually bigger than the L2 execution fee.
+var GasPriceOracle = common.HexToAddress("0x420000000000000000000000000000000000000F")
+
+var gasPriceOracleABI = func() *abi.ABI {
+	parsed, err := abi.JSON(bytes.NewReader([]byte(`[
+	{"inputs":[{"name":"_data","type":"bytes"}],"name":"getL1Fee","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"l1BaseFee","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}
+	]`)))
+	if err != nil {
+		logger.GlobalLogger.Fatalf("Failed parsing GasPriceOracle ABI: %v", err)
+	}
+	return &parsed
+}()
+
+// l1Oracle remembers whether the chain has the GasPriceOracle predeploy.
+// Only a definitive answer is kept; a failed probe is repeated.
+type l1Oracle struct {
+	mu        sync.Mutex
+	probed    bool
+	available bool
+}
+
+// GasValues is the fee estimate of a transaction. L1Fee is zero on chains
+// without the GasPriceOracle.
+type GasValues struct {
+	GasLimit uint64
+	TipCap   *big.Int
+	FeeCap   *big.Int
+	L1Fee    *big.Int
+}
+
+// L2Cost is the maximum execution fee: gas limit by fee cap.
+func (g *GasValues) L2Cost() *big.Int {
+	return new(big.Int).Mul(new(big.Int).SetUint64(g.GasLimit), g.FeeCap)
+}
+
+// Cost is the maximum total fee of the transaction.
+func (g *GasValues) Cost() *big.Int {
+	return new(big.Int).Add(g.L2Cost(), g.L1Fee)
+}
+
+// hasL1Oracle probes the code of the GasPriceOracle until the node answers.
+// While the probe fails the L1 fee is treated as zero.
+func (c *Client) hasL1Oracle(ctx context.Context) bool {
+	c.l1.mu.Lock()
+	defer c.l1.mu.Unlock()
+
+	if c.l1.probed {
+		return c.l1.available
+	}
+
+	probeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	code, err := c.Client.CodeAt(probeCtx, GasPriceOracle, nil)
+	if err != nil {
+		logger.GlobalLogger.Warnf("[%s] Failed to probe GasPriceOracle, L1 data fee is not included: %v", c.Chain, err)
+		return false
+	}
+
+	c.l1.probed = true
+	c.l1.available = len(code) > 0
+	if c.l1.available {
+		logger.GlobalLogger.Infof("[%s] GasPriceOracle found, L1 data fee is included in gas estimates", c.Chain)
+	}
+	return c.l1.available
+}
+
+// L1BaseFee returns the L1 base fee known to the L2.
+func (c *Client) L1BaseFee(ctx context.Context) (*big.Int, error) {
+	data, err := gasPriceOracleABI.Pack("l1BaseFee")
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack l1BaseFee: %w", err)
+	}
+
+	result, err := c.Client.CallContract(ctx, ethereum.CallMsg{To: &GasPriceOracle, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call l1BaseFee: %w", err)
+	}
+
+	var fee *big.Int
+	if err := gasPriceOracleABI.UnpackIntoInterface(&fee, "l1BaseFee", result); err != nil {
+		return nil, fmt.Errorf("failed to unpack l1BaseFee: %w", err)
+	}
+	return fee, nil
+}
+
+// L1Fee returns the L1 data fee of the unsigned transaction. The signature
+// adds a fixed overhead that the oracle already accounts for.
+func (c *Client) L1Fee(ctx context.Context, tx *types.Transaction) (*big.Int, error) {
+	if !c.hasL1Oracle(ctx) {
+		return big.NewInt(0), nil
+	}
+
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	data, err := gasPriceOracleABI.Pack("getL1Fee", raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack getL1Fee: %w", err)
+	}
+
+	result, err := c.Client.CallContract(ctx, ethereum.CallMsg{To: &GasPriceOracle, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call getL1Fee: %w", err)
+	}
+
+	var fee *big.Int
+	if err := gasPriceOracleABI.UnpackIntoInterface(&fee, "getL1Fee", result); err != nil {
+		return nil, fmt.Errorf("failed to unpack getL1Fee: %w", err)
+	}
+	return fee, nil
+}
@@ -0,0 +1,215 @@
+package ethClient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"lisk/globals"
+	"lisk/logger"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Multicall3 has the same address on every chain it is deployed to.
+var Multicall3 = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// maxCallsPerBatch keeps a single eth_call well below the gas and response
+// size limits of public RPC nodes.
+const maxCallsPerBatch = 300
+
+var multicallABI = func() *abi.ABI {
+	parsed, err := abi.JSON(bytes.NewReader([]byte(`[
+	{"inputs":[{"components":[{"name":"target","type":"address"},{"name":"allowFailure","type":"bool"},{"name":"callData","type":"bytes"}],"name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"name":"success","type":"bool"},{"name":"returnData","type":"bytes"}],"name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"},
+	{"inputs":[{"name":"addr","type":"address"}],"name":"getEthBalance","outputs":[{"name":"balance","type":"uint256"}],"stateMutability":"view","type":"function"}
+	]`)))
+	if err != nil {
+		logger.GlobalLogger.Fatalf("Failed parsing Multicall3 ABI: %v", err)
+	}
+	return &parsed
+}()
+
+// Call is one read of a Multicall3 batch.
+type Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// CallResult is the answer to the Call with the same index.
+type CallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// BalanceKey addresses one balance of a batch read.
+type BalanceKey struct {
+	Owner common.Address
+	Token common.Address
+}
+
+// AllowanceKey addresses one allowance of a batch read.
+type AllowanceKey struct {
+	Token   common.Address
+	Owner   common.Address
+	Spender common.Address
+}
+
+// Multicall runs the calls through Multicall3.aggregate3, splitting them into
+// batches of maxCallsPerBatch. Failed calls are reported per result.
+func (c *Client) Multicall(ctx context.Context, calls []Call) ([]CallResult, error) {
+	results := make([]CallResult, 0, len(calls))
+
+	for start := 0; start < len(calls); start += maxCallsPerBatch {
+		batch := calls[start:min(start+maxCallsPerBatch, len(calls))]
+
+		data, err := multicallABI.Pack("aggregate3", batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack aggregate3: %w", err)
+		}
+
+		response, err := c.Client.CallContract(ctx, ethereum.CallMsg{To: &Multicall3, Data: data}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("aggregate3 call failed: %w", asRevertError(err))
+		}
+
+		var batchResults []CallResult
+		if err := multicallABI.UnpackIntoInterface(&batchResults, "aggregate3", response); err != nil {
+			return nil, fmt.Errorf("failed to unpack aggregate3: %w", err)
+		}
+		if len(batchResults) != len(batch) {
+			return nil, fmt.Errorf("aggregate3 returned %d results for %d calls", len(batchResults), len(batch))
+		}
+
+		results = append(results, batchResults...)
+	}
+
+	return results, nil
+}
+
+// BatchBalances reads the balance of every token for every owner. The native
+// coin (see IsNativeToken) is read with Multicall3.getEthBalance. A failed
+// read does not fail the batch: its key is missing from the balances and its
+// error is in the second map.
+func (c *Client) BatchBalances(ctx context.Context, owners, tokens []common.Address) (map[BalanceKey]*big.Int, map[BalanceKey]error, error) {
+	keys := make([]BalanceKey, 0, len(owners)*len(tokens))
+	calls := make([]Call, 0, len(owners)*len(tokens))
+
+	for _, owner := range owners {
+		for _, token := range tokens {
+			call := Call{Target: token, AllowFailure: true}
+
+			var err error
+			if IsNativeToken(token) {
+				call.Target = Multicall3
+				call.CallData, err = multicallABI.Pack("getEthBalance", owner)
+			} else {
+				call.CallData, err = globals.Erc20ABI.Pack("balanceOf", owner)
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to pack balance call: %w", err)
+			}
+
+			keys = append(keys, BalanceKey{Owner: owner, Token: token})
+			calls = append(calls, call)
+		}
+	}
+
+	results, err := c.Multicall(ctx, calls)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	balances := make(map[BalanceKey]*big.Int, len(keys))
+	failed := make(map[BalanceKey]error)
+	for i, result := range results {
+		balance, err := unpackUint(result)
+		if err != nil {
+			failed[keys[i]] = fmt.Errorf("balance of %s for %s: %w", keys[i].Token.Hex(), keys[i].Owner.Hex(), err)
+			continue
+		}
+		balances[keys[i]] = balance
+	}
+
+	return balances, failed, nil
+}
+
+// BatchAllowances reads all allowances in one batch.
+func (c *Client) BatchAllowances(ctx context.Context, keys []AllowanceKey) (map[AllowanceKey]*big.Int, error) {
+	calls := make([]Call, 0, len(keys))
+	for _, key := range keys {
+		data, err := globals.Erc20ABI.Pack("allowance", key.Owner, key.Spender)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack allowance call: %w", err)
+		}
+		calls = append(calls, Call{Target: key.Token, AllowFailure: true, CallData: data})
+	}
+
+	results, err := c.Multicall(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	allowances := make(map[AllowanceKey]*big.Int, len(keys))
+	for i, result := range results {
+		allowance, err := unpackUint(result)
+		if err != nil {
+			return nil, fmt.Errorf("allowance of %s for %s: %w", keys[i].Token.Hex(), keys[i].Spender.Hex(), err)
+		}
+		allowances[keys[i]] = allowance
+	}
+
+	return allowances, nil
+}
+
+// BatchDecimals reads decimals() of every token. The native coin has 18.
+func (c *Client) BatchDecimals(ctx context.Context, tokens []common.Address) (map[common.Address]int, error) {
+	decimals := make(map[common.Address]int, len(tokens))
+
+	var (
+		erc20 []common.Address
+		calls []Call
+	)
+	for _, token := range tokens {
+		if IsNativeToken(token) {
+			decimals[token] = 18
+			continue
+		}
+
+		data, err := globals.Erc20ABI.Pack("decimals")
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack decimals call: %w", err)
+		}
+		erc20 = append(erc20, token)
+		calls = append(calls, Call{Target: token, AllowFailure: true, CallData: data})
+	}
+
+	results, err := c.Multicall(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range results {
+		value, err := unpackUint(result)
+		if err != nil {
+			return nil, fmt.Errorf("decimals of %s: %w", erc20[i].Hex(), err)
+		}
+		decimals[erc20[i]] = int(value.Int64())
+	}
+
+	return decimals, nil
+}
+
+// unpackUint reads a single uint word, which is the answer of balanceOf,
+// allowance, decimals and getEthBalance.
+func unpackUint(result CallResult) (*big.Int, error) {
+	if !result.Success {
+		return nil, fmt.Errorf("call failed: %w", decodeRevert(result.ReturnData))
+	}
+	if len(result.ReturnData) < 32 {
+		return nil, fmt.Errorf("unexpected return data 0x%x", result.ReturnData)
+	}
+	return new(big.Int).SetBytes(result.ReturnData[:32]), nil
+}
@@ -0,0 +1,198 @@
+package ethClient
+
+import (
+	"context"
+	"fmt"
+	"lisk/logger"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// nonceGapGrace is how long a lagging node gets before a lower pending nonce
+// is treated as a gap.
+var nonceGapGrace = 3 * time.Second
+
+type nonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+// NonceOutcome tells the manager what happened to a reserved nonce.
+type NonceOutcome int
+
+const (
+	// NonceUnused: the transaction was never broadcast, the nonce is reused.
+	NonceUnused NonceOutcome = iota
+	// NonceMined: the transaction was included in a block.
+	NonceMined
+	// NoncePending: the transaction was broadcast but not mined yet.
+	NoncePending
+	// NonceInvalid: the node rejected the nonce, the account is resynced.
+	NonceInvalid
+)
+
+// NonceManager hands out nonces locally per address, so consecutive
+// transactions do not depend on how fast the RPC node updates its pending
+// nonce. Sends of one address are serialized: a reservation holds the
+// address until it is released.
+type NonceManager struct {
+	source   nonceSource
+	chain    string
+	mu       sync.Mutex
+	accounts map[common.Address]*accountNonce
+}
+
+type accountNonce struct {
+	mu       sync.Mutex
+	synced   bool
+	next     uint64
+	inflight map[uint64]bool
+}
+
+type NonceReservation struct {
+	Nonce   uint64
+	address common.Address
+	state   *accountNonce
+	manager *NonceManager
+	done    bool
+}
+
+func NewNonceManager(source nonceSource, chain string) *NonceManager {
+	return &NonceManager{
+		source:   source,
+		chain:    chain,
+		accounts: make(map[common.Address]*accountNonce),
+	}
+}
+
+// Reserve locks the address and returns the next nonce for it. The
+// reservation must be released exactly once.
+func (m *NonceManager) Reserve(ctx context.Context, address common.Address) (*NonceReservation, error) {
+	m.mu.Lock()
+	state, exists := m.accounts[address]
+	if !exists {
+		state = &accountNonce{inflight: make(map[uint64]bool)}
+		m.accounts[address] = state
+	}
+	m.mu.Unlock()
+
+	locked := make(chan struct{})
+	go func() {
+		state.mu.Lock()
+		close(locked)
+	}()
+
+	select {
+	case <-locked:
+	case <-ctx.Done():
+		// The goroutine still takes the lock eventually, hand it back.
+		go func() {
+			<-locked
+			state.mu.Unlock()
+		}()
+		return nil, ctx.Err()
+	}
+
+	if err := m.check(ctx, address, state); err != nil {
+		state.mu.Unlock()
+		return nil, err
+	}
+
+	nonce := state.next
+	state.next++
+
+	return &NonceReservation{Nonce: nonce, address: address, state: state, manager: m}, nil
+}
+
+// check syncs the local nonce with the node and detects transactions sent
+// outside of the manager and gaps left by dropped transactions.
+// The caller holds state.mu.
+func (m *NonceManager) check(ctx context.Context, address common.Address, state *accountNonce) error {
+	pending, err := m.source.PendingNonceAt(ctx, address)
+	if err != nil {
+		if !state.synced {
+			return fmt.Errorf("failed to get nonce for %s: %w", address.Hex(), err)
+		}
+		logger.GlobalLogger.Warnf("[NONCE][%s] Failed to check nonce for %s, using local %d: %v", m.chain, address.Hex(), state.next, err)
+		return nil
+	}
+
+	switch {
+	case !state.synced:
+		state.next = pending
+		state.synced = true
+	case pending > state.next:
+		logger.GlobalLogger.Warnf("[NONCE][%s] %s: node nonce %d is ahead of local %d, transactions were sent outside of this run", m.chain, address.Hex(), pending, state.next)
+		state.next = pending
+	case pending < state.next && !m.coveredByInflight(state, pending):
+		// Every nonce below next was mined according to its receipt, so the
+		// node is either lagging or a transaction was dropped. Give the node a
+		// moment before treating it as a gap.
+		if err := sleepContext(ctx, nonceGapGrace); err != nil {
+			return err
+		}
+		latest, err := m.source.NonceAt(ctx, address, nil)
+		if err != nil || latest >= state.next {
+			return nil
+		}
+		if pending, err = m.source.PendingNonceAt(ctx, address); err != nil || pending >= state.next {
+			return nil
+		}
+		logger.GlobalLogger.Warnf("[NONCE][%s] %s: nonce gap detected (local %d, node %d), resyncing", m.chain, address.Hex(), state.next, pending)
+		state.next = pending
+		state.inflight = make(map[uint64]bool)
+	}
+
+	return nil
+}
+
+// coveredByInflight reports whether the nonces between pending and next
+// belong to transactions that were broadcast but not mined yet.
+func (m *NonceManager) coveredByInflight(state *accountNonce, pending uint64) bool {
+	for n := pending; n < state.next; n++ {
+		if !state.inflight[n] {
+			return false
+		}
+	}
+	return true
+}
+
+// Release unlocks the address and records the outcome of the nonce.
+func (r *NonceReservation) Release(outcome NonceOutcome) {
+	if r.done {
+		return
+	}
+	r.done = true
+
+	state := r.state
+	defer state.mu.Unlock()
+
+	switch outcome {
+	case NonceUnused:
+		if state.next == r.Nonce+1 {
+			state.next = r.Nonce
+		}
+	case NonceMined:
+		delete(state.inflight, r.Nonce)
+	case NoncePending:
+		state.inflight[r.Nonce] = true
+	case NonceInvalid:
+		state.synced = false
+		state.inflight = make(map[uint64]bool)
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
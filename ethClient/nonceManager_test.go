@@ -0,0 +1,176 @@
+package ethClient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeNonces is a node that reports fixed pending and latest nonces.
+type fakeNonces struct {
+	mu      sync.Mutex
+	pending uint64
+	latest  uint64
+	err     error
+}
+
+func (f *fakeNonces) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pending, f.err
+}
+
+func (f *fakeNonces) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.latest, f.err
+}
+
+func (f *fakeNonces) set(pending, latest uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending, f.latest = pending, latest
+}
+
+var testAddress = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+func init() {
+	nonceGapGrace = 10 * time.Millisecond
+}
+
+func reserve(t *testing.T, m *NonceManager) *NonceReservation {
+	t.Helper()
+	r, err := m.Reserve(context.Background(), testAddress)
+	if err != nil {
+		t.Fatalf("Reserve: %v", err)
+	}
+	return r
+}
+
+func TestNonceReserveSequence(t *testing.T) {
+	node := &fakeNonces{pending: 5, latest: 5}
+	m := NewNonceManager(node, "test")
+
+	r := reserve(t, m)
+	if r.Nonce != 5 {
+		t.Fatalf("first nonce = %d, want 5 from the node", r.Nonce)
+	}
+	r.Release(NoncePending)
+
+	// The node has not seen the pending transaction yet, the local nonce is
+	// used without waiting for it.
+	r = reserve(t, m)
+	if r.Nonce != 6 {
+		t.Fatalf("second nonce = %d, want 6", r.Nonce)
+	}
+	r.Release(NoncePending)
+}
+
+func TestNonceReleaseUnused(t *testing.T) {
+	node := &fakeNonces{pending: 3, latest: 3}
+	m := NewNonceManager(node, "test")
+
+	reserve(t, m).Release(NonceUnused)
+	if r := reserve(t, m); r.Nonce != 3 {
+		t.Fatalf("nonce after an unused release = %d, want 3 again", r.Nonce)
+	}
+}
+
+func TestNonceReleaseTwice(t *testing.T) {
+	m := NewNonceManager(&fakeNonces{}, "test")
+
+	r := reserve(t, m)
+	r.Release(NonceUnused)
+	r.Release(NonceUnused) // must not unlock the address a second time
+
+	done := make(chan struct{})
+	go func() {
+		reserve(t, m).Release(NonceMined)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("address is still locked after the release")
+	}
+}
+
+func TestNonceNodeAhead(t *testing.T) {
+	node := &fakeNonces{pending: 1, latest: 1}
+	m := NewNonceManager(node, "test")
+	reserve(t, m).Release(NonceMined)
+
+	// A transaction was sent from another wallet.
+	node.set(4, 4)
+	if r := reserve(t, m); r.Nonce != 4 {
+		t.Fatalf("nonce = %d, want 4 from the node", r.Nonce)
+	}
+}
+
+func TestNonceInvalidResyncs(t *testing.T) {
+	node := &fakeNonces{pending: 7, latest: 7}
+	m := NewNonceManager(node, "test")
+	reserve(t, m).Release(NoncePending)
+	reserve(t, m).Release(NonceInvalid)
+
+	node.set(7, 7)
+	if r := reserve(t, m); r.Nonce != 7 {
+		t.Fatalf("nonce after an invalid release = %d, want 7 from the node", r.Nonce)
+	}
+}
+
+func TestNonceGapResyncs(t *testing.T) {
+	node := &fakeNonces{pending: 2, latest: 2}
+	m := NewNonceManager(node, "test")
+	reserve(t, m).Release(NonceMined)
+	node.set(3, 3)
+	reserve(t, m).Release(NonceMined)
+
+	// Nonce 3 was reported mined but the node still expects 3: it was
+	// dropped, the gap is resynced after the grace period.
+	if r := reserve(t, m); r.Nonce != 3 {
+		t.Fatalf("nonce after a gap = %d, want 3", r.Nonce)
+	}
+}
+
+func TestNonceLaggingNodeNoResync(t *testing.T) {
+	node := &fakeNonces{pending: 2, latest: 2}
+	m := NewNonceManager(node, "test")
+	reserve(t, m).Release(NonceMined)
+
+	// The pending nonce lags, but the mined nonce has caught up.
+	node.set(2, 3)
+	if r := reserve(t, m); r.Nonce != 3 {
+		t.Fatalf("nonce = %d, want 3", r.Nonce)
+	}
+}
+
+func TestNonceFirstSyncError(t *testing.T) {
+	node := &fakeNonces{err: errors.New("connection refused")}
+	m := NewNonceManager(node, "test")
+
+	if _, err := m.Reserve(context.Background(), testAddress); err == nil {
+		t.Fatal("expected an error without a nonce from the node")
+	}
+}
+
+func TestNonceReserveCancelled(t *testing.T) {
+	m := NewNonceManager(&fakeNonces{}, "test")
+	held := reserve(t, m)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := m.Reserve(ctx, testAddress); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Reserve of a locked address = %v, want the context error", err)
+	}
+
+	held.Release(NonceUnused)
+	if r := reserve(t, m); r.Nonce != 0 {
+		t.Fatalf("nonce after the release = %d, want 0", r.Nonce)
+	}
+}
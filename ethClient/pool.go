@@ -0,0 +1,484 @@
+package ethClient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lisk/config"
+	"lisk/globals"
+	"lisk/logger"
+	"lisk/retry"
+	"math/big"
+	"math/rand"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	maxConsecutiveErrors  = 3
+	ejectDuration         = time.Minute
+	maxEjectDuration      = 10 * time.Minute
+	healthCheckInterval   = 30 * time.Second
+	healthCheckTimeout    = 5 * time.Second
+	statusLogInterval     = 10 * time.Minute
+	latencySmoothingRatio = 0.3
+)
+
+// Pool spreads the requests of one chain over several RPC endpoints. Every
+// endpoint tracks its latency and errors; an endpoint that keeps failing or
+// hits a rate limit is ejected for a while and brought back by the health
+// check. Idempotent calls are retried on another endpoint.
+type Pool struct {
+	chain     string
+	chainID   *big.Int
+	endpoints []*endpoint
+	stop      chan struct{}
+	wg        sync.WaitGroup
+}
+
+type endpoint struct {
+	url    string
+	weight int
+	client *ethclient.Client
+
+	mu           sync.Mutex
+	latency      time.Duration
+	requests     uint64
+	failures     uint64
+	consecutive  int
+	ejections    int
+	ejectedUntil time.Time
+	lastError    string
+}
+
+// NewPool dials every endpoint and checks that they serve the same chain.
+// Endpoints that do not answer start ejected and are brought back by the
+// health checker. Endpoints that cannot be dialed (e.g. an invalid URL) or
+// serve another chain are dropped. The pool fails only when none of them
+// answers.
+func NewPool(ctx context.Context, chain string, endpoints config.RPCEndpoints) (*Pool, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no RPC endpoints for %s", chain)
+	}
+
+	pool := &Pool{chain: chain, stop: make(chan struct{})}
+
+	for _, cfg := range endpoints {
+		client, err := ethclient.DialContext(ctx, cfg.URL)
+		if err != nil {
+			logger.GlobalLogger.Warnf("[RPC][%s] Failed to dial %s: %v", chain, cfg.URL, err)
+			continue
+		}
+
+		e := &endpoint{url: cfg.URL, weight: cfg.Weight, client: client}
+
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		start := time.Now()
+		chainID, err := client.ChainID(checkCtx)
+		cancel()
+
+		switch {
+		case err != nil:
+			logger.GlobalLogger.Warnf("[RPC][%s] %s is not responding: %v", chain, cfg.URL, err)
+			e.recordFailure(err, true)
+		case pool.chainID != nil && pool.chainID.Cmp(chainID) != 0:
+			logger.GlobalLogger.Warnf("[RPC][%s] %s serves chain %v instead of %v, skipped", chain, cfg.URL, chainID, pool.chainID)
+			client.Close()
+			continue
+		default:
+			pool.chainID = chainID
+			e.recordSuccess(time.Since(start))
+		}
+
+		pool.endpoints = append(pool.endpoints, e)
+	}
+
+	if pool.chainID == nil {
+		pool.closeEndpoints()
+		return nil, fmt.Errorf("no RPC endpoint for %s is available", chain)
+	}
+
+	pool.wg.Add(1)
+	go pool.healthLoop()
+
+	logger.GlobalLogger.Infof("[RPC][%s] %s", chain, pool.Status())
+	return pool, nil
+}
+
+func (p *Pool) Close() {
+	close(p.stop)
+	p.wg.Wait()
+	p.closeEndpoints()
+}
+
+func (p *Pool) closeEndpoints() {
+	for _, e := range p.endpoints {
+		e.client.Close()
+	}
+}
+
+// Status returns a one-line summary of every endpoint of the pool.
+func (p *Pool) Status() string {
+	parts := make([]string, 0, len(p.endpoints))
+	now := time.Now()
+
+	for _, e := range p.endpoints {
+		e.mu.Lock()
+		state := "up"
+		if now.Before(e.ejectedUntil) {
+			state = fmt.Sprintf("ejected %s", e.ejectedUntil.Sub(now).Round(time.Second))
+		}
+		parts = append(parts, fmt.Sprintf("%s [%s, w=%d, %dms, %d/%d failed]",
+			e.url, state, e.weight, e.latency.Milliseconds(), e.failures, e.requests))
+		e.mu.Unlock()
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+func (p *Pool) healthLoop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	lastStatus := time.Now()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			for _, e := range p.endpoints {
+				p.checkEndpoint(e)
+			}
+			if time.Since(lastStatus) >= statusLogInterval {
+				logger.GlobalLogger.Infof("[RPC][%s] %s", p.chain, p.Status())
+				lastStatus = time.Now()
+			}
+		}
+	}
+}
+
+func (p *Pool) checkEndpoint(e *endpoint) {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	_, err := e.client.BlockNumber(ctx)
+	if err != nil {
+		if e.recordFailure(err, isRateLimitError(err)) {
+			logger.GlobalLogger.Warnf("[RPC][%s] %s ejected: %v", p.chain, e.url, err)
+		}
+		return
+	}
+
+	if e.recordSuccess(time.Since(start)) {
+		logger.GlobalLogger.Infof("[RPC][%s] %s is healthy again", p.chain, e.url)
+	}
+}
+
+// recordSuccess updates the latency and returns true if the endpoint was
+// ejected before.
+func (e *endpoint) recordSuccess(latency time.Duration) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	e.consecutive = 0
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(float64(e.latency)*(1-latencySmoothingRatio) + float64(latency)*latencySmoothingRatio)
+	}
+
+	if e.ejectedUntil.IsZero() {
+		return false
+	}
+	e.ejectedUntil = time.Time{}
+	e.ejections = 0
+	return true
+}
+
+// recordFailure counts the error and returns true if the endpoint was ejected
+// by it.
+func (e *endpoint) recordFailure(err error, ejectNow bool) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.requests++
+	e.failures++
+	e.consecutive++
+	e.lastError = err.Error()
+
+	if !ejectNow && e.consecutive < maxConsecutiveErrors {
+		return false
+	}
+	if time.Now().Before(e.ejectedUntil) {
+		return false
+	}
+
+	duration := ejectDuration << e.ejections
+	if duration > maxEjectDuration {
+		duration = maxEjectDuration
+	}
+	e.ejections++
+	e.ejectedUntil = time.Now().Add(duration)
+	return true
+}
+
+func (e *endpoint) score(now time.Time) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if now.Before(e.ejectedUntil) {
+		return 0
+	}
+	// Slow endpoints keep their share but get less of it.
+	return float64(e.weight) / (1 + e.latency.Seconds())
+}
+
+// pick returns a random healthy endpoint weighted by its score. When every
+// endpoint is ejected, the one that comes back first is used.
+func (p *Pool) pick(exclude map[*endpoint]bool) *endpoint {
+	now := time.Now()
+
+	var (
+		total      float64
+		candidates []*endpoint
+		scores     []float64
+	)
+	for _, e := range p.endpoints {
+		if exclude[e] {
+			continue
+		}
+		if s := e.score(now); s > 0 {
+			candidates = append(candidates, e)
+			scores = append(scores, s)
+			total += s
+		}
+	}
+
+	if len(candidates) == 0 {
+		var best *endpoint
+		for _, e := range p.endpoints {
+			if exclude[e] {
+				continue
+			}
+			if best == nil || e.ejectedAt().Before(best.ejectedAt()) {
+				best = e
+			}
+		}
+		return best
+	}
+
+	r := rand.Float64() * total
+	for i, e := range candidates {
+		if r < scores[i] {
+			return e
+		}
+		r -= scores[i]
+	}
+	return candidates[len(candidates)-1]
+}
+
+func (e *endpoint) ejectedAt() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.ejectedUntil
+}
+
+// poolCall runs fn on an endpoint of the pool. Failures of the endpoint itself
+// (network errors, rate limits, 5xx) are retried on another endpoint by the
+// RPC retry policy when the call is idempotent; answers of the node such as
+// reverts are returned as is. The backoff delay is only used once every
+// endpoint has been tried.
+func poolCall[T any](ctx context.Context, p *Pool, idempotent bool, fn func(*ethclient.Client) (T, error)) (T, error) {
+	var (
+		zero   T
+		policy = retry.RPC
+		tried  = make(map[*endpoint]bool)
+	)
+
+	for attempt := 1; ; attempt++ {
+		e := p.pick(tried)
+		if e == nil {
+			// Every endpoint failed once, start another round after a pause.
+			if err := sleepContext(ctx, policy.Delay(attempt-1)); err != nil {
+				return zero, err
+			}
+			tried = make(map[*endpoint]bool)
+			e = p.pick(tried)
+		}
+		tried[e] = true
+
+		start := time.Now()
+		result, err := fn(e.client)
+		if err == nil || !isEndpointError(err) {
+			e.recordSuccess(time.Since(start))
+			return result, err
+		}
+
+		if ctx.Err() != nil {
+			return zero, err
+		}
+
+		if e.recordFailure(err, isRateLimitError(err)) {
+			logger.GlobalLogger.Warnf("[RPC][%s] %s ejected: %v", p.chain, e.url, err)
+		}
+
+		if isRateLimitError(err) {
+			err = fmt.Errorf("%w on %s: %w", globals.ErrRPCRateLimited, p.chain, err)
+		}
+		if !idempotent || !policy.ShouldRetry(err, attempt) {
+			return zero, err
+		}
+		logger.GlobalLogger.Warnf("[RPC][%s] %s failed (%s): %v. Retry %d of %d", p.chain, e.url, retry.Classify(err), err, attempt, policy.MaxAttempts-1)
+	}
+}
+
+// isEndpointError separates failures of the endpoint from regular answers of
+// the node, e.g. a reverted call or a missing receipt.
+func isEndpointError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, ethereum.NotFound) {
+		return false
+	}
+	if isRateLimitError(err) {
+		return true
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == 429
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	// A JSON-RPC error is a regular answer of the node.
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+func isRateLimitError(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32005 {
+		return true
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == 429 {
+		return true
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	return strings.Contains(errMsg, "free tier limits") ||
+		strings.Contains(errMsg, "rate limit") ||
+		strings.Contains(errMsg, "too many requests")
+}
+
+func (p *Pool) NetworkID(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).Set(p.chainID), nil
+}
+
+func (p *Pool) BlockNumber(ctx context.Context) (uint64, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) (uint64, error) {
+		return c.BlockNumber(ctx)
+	})
+}
+
+func (p *Pool) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) (*big.Int, error) {
+		return c.BalanceAt(ctx, account, blockNumber)
+	})
+}
+
+func (p *Pool) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) ([]byte, error) {
+		return c.CallContract(ctx, msg, blockNumber)
+	})
+}
+
+func (p *Pool) PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) ([]byte, error) {
+		return c.PendingCallContract(ctx, msg)
+	})
+}
+
+func (p *Pool) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) (*types.Header, error) {
+		return c.HeaderByNumber(ctx, number)
+	})
+}
+
+func (p *Pool) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) (*big.Int, error) {
+		return c.SuggestGasTipCap(ctx)
+	})
+}
+
+func (p *Pool) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) (uint64, error) {
+		return c.EstimateGas(ctx, msg)
+	})
+}
+
+func (p *Pool) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) (uint64, error) {
+		return c.PendingNonceAt(ctx, account)
+	})
+}
+
+func (p *Pool) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) ([]byte, error) {
+		return c.CodeAt(ctx, account, blockNumber)
+	})
+}
+
+func (p *Pool) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) (uint64, error) {
+		return c.NonceAt(ctx, account, blockNumber)
+	})
+}
+
+func (p *Pool) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) (*types.Receipt, error) {
+		return c.TransactionReceipt(ctx, txHash)
+	})
+}
+
+// ReceiptL1Fee returns the L1 data fee an OP-Stack node reports in the
+// receipt. The go-ethereum receipt type does not decode this field.
+func (p *Pool) ReceiptL1Fee(ctx context.Context, txHash common.Hash) (*big.Int, error) {
+	return poolCall(ctx, p, true, func(c *ethclient.Client) (*big.Int, error) {
+		var receipt struct {
+			L1Fee *hexutil.Big `json:"l1Fee"`
+		}
+		if err := c.Client().CallContext(ctx, &receipt, "eth_getTransactionReceipt", txHash); err != nil {
+			return nil, err
+		}
+		if receipt.L1Fee == nil {
+			return big.NewInt(0), nil
+		}
+		return receipt.L1Fee.ToInt(), nil
+	})
+}
+
+// SendTransaction broadcasts through a single endpoint. A failed broadcast is
+// not repeated elsewhere: the node may have accepted the transaction anyway.
+func (p *Pool) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := poolCall(ctx, p, false, func(c *ethclient.Client) (struct{}, error) {
+		return struct{}{}, c.SendTransaction(ctx, tx)
+	})
+	return err
+}
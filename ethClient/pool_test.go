@@ -0,0 +1,169 @@
+package ethClient
+
+import (
+	"context"
+	"errors"
+	"lisk/config"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type stubChain struct{}
+
+func (stubChain) ChainId() *hexutil.Big       { return (*hexutil.Big)(hexutil.MustDecodeBig("0x46f")) }
+func (stubChain) BlockNumber() hexutil.Uint64 { return 100 }
+
+// stubNode is an RPC endpoint that answers 503 while it is down.
+type stubNode struct {
+	*httptest.Server
+	down  atomic.Bool
+	calls atomic.Int64
+}
+
+func newStubNode(t *testing.T) *stubNode {
+	t.Helper()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", stubChain{}); err != nil {
+		t.Fatal(err)
+	}
+
+	node := &stubNode{}
+	node.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		node.calls.Add(1)
+		if node.down.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		node.Close()
+		server.Stop()
+	})
+	return node
+}
+
+func newTestPool(t *testing.T, nodes ...*stubNode) *Pool {
+	t.Helper()
+
+	endpoints := make(config.RPCEndpoints, 0, len(nodes))
+	for _, node := range nodes {
+		endpoints = append(endpoints, config.RPCEndpoint{URL: node.URL, Weight: 1})
+	}
+	pool, err := NewPool(context.Background(), "test", endpoints)
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	return pool
+}
+
+func TestPoolUnreachableEndpointStartsEjected(t *testing.T) {
+	up, down := newStubNode(t), newStubNode(t)
+	down.down.Store(true)
+
+	pool := newTestPool(t, up, down)
+	if len(pool.endpoints) != 2 {
+		t.Fatalf("pool has %d endpoints, want 2", len(pool.endpoints))
+	}
+	ejected := pool.endpoints[1]
+	if ejected.score(time.Now()) != 0 {
+		t.Fatal("endpoint that did not answer is not ejected")
+	}
+
+	down.calls.Store(0)
+	for i := 0; i < 10; i++ {
+		if _, err := pool.BlockNumber(context.Background()); err != nil {
+			t.Fatalf("BlockNumber: %v", err)
+		}
+	}
+	if n := down.calls.Load(); n != 0 {
+		t.Fatalf("ejected endpoint got %d calls", n)
+	}
+
+	// The health check brings it back once it answers.
+	down.down.Store(false)
+	pool.checkEndpoint(ejected)
+	if ejected.score(time.Now()) == 0 {
+		t.Fatal("healthy endpoint is still ejected after the health check")
+	}
+}
+
+func TestPoolFailover(t *testing.T) {
+	a, b := newStubNode(t), newStubNode(t)
+	pool := newTestPool(t, a, b)
+
+	a.down.Store(true)
+	for i := 0; i < 20; i++ {
+		if _, err := pool.BlockNumber(context.Background()); err != nil {
+			t.Fatalf("BlockNumber with one endpoint down: %v", err)
+		}
+	}
+	if a.calls.Load() > 1+maxConsecutiveErrors {
+		t.Fatalf("failing endpoint got %d calls, it should be ejected after %d errors", a.calls.Load(), maxConsecutiveErrors)
+	}
+}
+
+func TestEndpointEjection(t *testing.T) {
+	e := &endpoint{url: "test", weight: 1}
+	err := errors.New("connection reset")
+
+	for i := 1; i < maxConsecutiveErrors; i++ {
+		if e.recordFailure(err, false) {
+			t.Fatalf("ejected after %d errors", i)
+		}
+	}
+	if !e.recordFailure(err, false) {
+		t.Fatalf("not ejected after %d errors", maxConsecutiveErrors)
+	}
+	if until := time.Until(e.ejectedUntil); until <= 0 || until > ejectDuration {
+		t.Fatalf("first ejection lasts %v, want %v", until, ejectDuration)
+	}
+
+	// Ejections of an endpoint that keeps failing get longer, up to the cap.
+	for i := 0; i < 10; i++ {
+		e.ejectedUntil = time.Time{}
+		e.recordFailure(err, true)
+	}
+	if until := time.Until(e.ejectedUntil); until <= maxEjectDuration-time.Second || until > maxEjectDuration {
+		t.Fatalf("repeated ejection lasts %v, want %v", until, maxEjectDuration)
+	}
+
+	if !e.recordSuccess(10 * time.Millisecond) {
+		t.Fatal("success did not report the recovery")
+	}
+	if e.score(time.Now()) == 0 || e.ejections != 0 {
+		t.Fatal("endpoint is not restored after a success")
+	}
+}
+
+func TestEndpointRateLimitEjectsNow(t *testing.T) {
+	e := &endpoint{url: "test", weight: 1}
+	if !e.recordFailure(errors.New("429 Too Many Requests"), true) {
+		t.Fatal("rate limited endpoint is not ejected at once")
+	}
+}
+
+func TestPoolPickAllEjected(t *testing.T) {
+	now := time.Now()
+	first := &endpoint{url: "first", weight: 1, ejectedUntil: now.Add(time.Minute)}
+	second := &endpoint{url: "second", weight: 1, ejectedUntil: now.Add(time.Second)}
+	pool := &Pool{endpoints: []*endpoint{first, second}}
+
+	if got := pool.pick(nil); got != second {
+		t.Fatalf("pick = %s, want the endpoint that comes back first", got.url)
+	}
+	if got := pool.pick(map[*endpoint]bool{second: true}); got != first {
+		t.Fatalf("pick with exclusion = %s, want first", got.url)
+	}
+	if got := pool.pick(map[*endpoint]bool{first: true, second: true}); got != nil {
+		t.Fatalf("pick with every endpoint excluded = %s, want nil", got.url)
+	}
+}
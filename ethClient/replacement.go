@@ -0,0 +1,208 @@
+package ethClient
+
+import (
+	"context"
+	"fmt"
+	"lisk/account"
+	"lisk/globals"
+	"lisk/logger"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const cancelGasLimit = 21000
+
+// TxCancelledError is returned when a stuck transaction was replaced by a
+// zero-value transfer to the sender. The nonce is used, the action is not done.
+type TxCancelledError struct {
+	Chain string
+	Hash  common.Hash
+}
+
+func (e *TxCancelledError) Error() string {
+	return fmt.Sprintf("transaction was cancelled by %s on %s", e.Hash.Hex(), e.Chain)
+}
+
+// waitWithReplacement waits for tx and replaces it while it is stuck: the same
+// nonce is rebroadcast with bumped fees every TxReplaceAfter seconds, after
+// TxMaxFeeBumps bumps a cancel transaction is sent. Every broadcast version is
+// watched, since any of them can be the one that gets mined. Replacements
+// that would go over the fee limits or the gas budget are not sent; the
+// transaction is then reported as pending after one more period.
+func (c *Client) waitWithReplacement(ctx context.Context, chainID *big.Int, signer account.Signer, tx *types.Transaction) (common.Hash, error) {
+	var (
+		sent      = []*types.Transaction{tx}
+		current   = tx
+		bumps     int
+		capped    bool // a replacement would go over the cost limits
+		cancel    *types.Transaction
+		replaceAt = time.Now().Add(time.Duration(globals.TxReplaceAfter) * time.Second)
+	)
+
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return current.Hash(), &TxPendingError{Chain: c.Chain, Hash: current.Hash()}
+		case <-ticker.C:
+		}
+
+		for i := len(sent) - 1; i >= 0; i-- {
+			receipt, err := c.Client.TransactionReceipt(ctx, sent[i].Hash())
+			if err != nil {
+				continue
+			}
+
+			mined := sent[i].Hash()
+			if mined != tx.Hash() {
+				logger.GlobalLogger.Infof("[NONCE: %v] Transaction %s was replaced, mined: https://blockscout.lisk.com/tx/%s", tx.Nonce(), tx.Hash().Hex(), mined.Hex())
+			}
+
+			switch {
+			case cancel != nil && mined == cancel.Hash():
+				c.recordReceipt(ctx, receipt, TxStatusCancelled)
+				return mined, &TxCancelledError{Chain: c.Chain, Hash: mined}
+			case receipt.Status != types.ReceiptStatusSuccessful:
+				c.recordReceipt(ctx, receipt, TxStatusFailed)
+				return mined, c.replayFailedTx(ctx, chainID, sent[i], receipt)
+			default:
+				c.recordReceipt(ctx, receipt, TxStatusSuccess)
+				return mined, nil
+			}
+		}
+
+		if time.Now().Before(replaceAt) {
+			continue
+		}
+		replaceAt = time.Now().Add(time.Duration(globals.TxReplaceAfter) * time.Second)
+
+		if cancel != nil || capped {
+			return current.Hash(), &TxPendingError{Chain: c.Chain, Hash: current.Hash()}
+		}
+
+		cancelling := bumps >= globals.TxMaxFeeBumps
+		next, err := c.bumpTransaction(ctx, chainID, signer, current, cancelling)
+		if err != nil {
+			logger.GlobalLogger.Warnf("[NONCE: %v] Failed to replace transaction %s: %v", tx.Nonce(), current.Hash().Hex(), err)
+			continue
+		}
+
+		if err := c.checkReplacementCost(ctx, next); err != nil {
+			logger.GlobalLogger.Warnf("[NONCE: %v] Transaction %s is not replaced: %v", tx.Nonce(), current.Hash().Hex(), err)
+			capped = true
+			continue
+		}
+
+		if err := c.Client.SendTransaction(ctx, next); err != nil && !isAlreadyKnown(err) {
+			// "nonce too low" means one of the sent versions is already mined,
+			// the next poll will find its receipt. current stays the last
+			// broadcast version, the next bump is signed from it again.
+			logger.GlobalLogger.Warnf("[NONCE: %v] Replacement %s was rejected: %v", tx.Nonce(), next.Hash().Hex(), err)
+			continue
+		}
+
+		if cancelling {
+			c.journalSent(withTxAction(ctx, TxActionCancel), chainID, next, nil, current.Hash())
+		} else {
+			c.journalSent(ctx, chainID, next, nil, current.Hash())
+		}
+
+		if cancelling {
+			cancel = next
+			logger.GlobalLogger.Warnf("[NONCE: %v] Transaction is still stuck after %d fee bumps, cancel sent: https://blockscout.lisk.com/tx/%s", tx.Nonce(), bumps, next.Hash().Hex())
+		} else {
+			bumps++
+			logger.GlobalLogger.Warnf("[NONCE: %v] Transaction is stuck, speed-up %d/%d sent: https://blockscout.lisk.com/tx/%s", tx.Nonce(), bumps, globals.TxMaxFeeBumps, next.Hash().Hex())
+		}
+
+		current = next
+		sent = append(sent, next)
+	}
+}
+
+// bumpTransaction signs a copy of tx with raised fees. With cancel set the copy
+// is a zero-value transfer to the sender.
+func (c *Client) bumpTransaction(ctx context.Context, chainID *big.Int, signer account.Signer, tx *types.Transaction, cancel bool) (*types.Transaction, error) {
+	tipCap := bumpFee(tx.GasTipCap())
+	feeCap := bumpFee(tx.GasFeeCap())
+
+	header, err := c.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get header: %w", err)
+	}
+	if suggested, err := c.Client.SuggestGasTipCap(ctx); err == nil && suggested.Cmp(tipCap) > 0 {
+		tipCap = suggested
+	}
+	if minFeeCap := new(big.Int).Add(header.BaseFee, tipCap); minFeeCap.Cmp(feeCap) > 0 {
+		feeCap = minFeeCap
+	}
+
+	replacement := &types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     tx.Nonce(),
+		GasTipCap: tipCap,
+		GasFeeCap: feeCap,
+		Gas:       tx.Gas(),
+		To:        tx.To(),
+		Value:     tx.Value(),
+		Data:      tx.Data(),
+	}
+
+	if cancel {
+		sender := signer.Address()
+		replacement.To = &sender
+		replacement.Value = big.NewInt(0)
+		replacement.Data = nil
+		replacement.Gas = cancelGasLimit
+	}
+
+	signedTx, err := signer.SignTx(ctx, types.NewTx(replacement), chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	return signedTx, nil
+}
+
+// checkReplacementCost applies the limits of GetGasValues and the cost check
+// of the action (gas budgets) to a replacement before it is broadcast.
+func (c *Client) checkReplacementCost(ctx context.Context, tx *types.Transaction) error {
+	l1Fee, err := c.L1Fee(ctx, tx)
+	if err != nil {
+		return fmt.Errorf("failed to get L1 fee: %w", err)
+	}
+	values := &GasValues{GasLimit: tx.Gas(), TipCap: tx.GasTipCap(), FeeCap: tx.GasFeeCap(), L1Fee: l1Fee}
+
+	if tx.GasFeeCap().Cmp(globals.AttentionGwei) > 0 {
+		return fmt.Errorf("fee cap %v is above attention_gwei", tx.GasFeeCap())
+	}
+	if globals.AttentionTxCost != nil && values.Cost().Cmp(globals.AttentionTxCost) > 0 {
+		return fmt.Errorf("cost %s ETH is above attention_tx_cost", formatCost(values))
+	}
+	if checkCost := txMetaFrom(ctx).CheckCost; checkCost != nil {
+		return checkCost(values.Cost())
+	}
+	return nil
+}
+
+func (c *Client) senderOf(chainID *big.Int, tx *types.Transaction) common.Address {
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx)
+	if err != nil {
+		return common.Address{}
+	}
+	return sender
+}
+
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(int64(100+globals.TxFeeBumpPercent)))
+	bumped.Div(bumped, big.NewInt(100))
+	// Tiny fees would round down to the same value.
+	if bumped.Cmp(fee) <= 0 {
+		bumped.Add(fee, big.NewInt(1))
+	}
+	return bumped
+}
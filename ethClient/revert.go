@@ -0,0 +1,197 @@
+package ethClient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"lisk/globals"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+
+	panicReasons = map[uint64]string{
+		0x01: "assertion failed",
+		0x11: "arithmetic overflow or underflow",
+		0x12: "division by zero",
+		0x21: "invalid enum value",
+		0x22: "invalid storage byte array",
+		0x31: "pop on empty array",
+		0x32: "array index out of bounds",
+		0x41: "out of memory",
+		0x51: "call to zero-initialized function",
+	}
+)
+
+// RevertError is a decoded revert of a call or a mined transaction.
+// Name is "Error" for require/revert messages, "Panic" for Solidity panics and
+// the error name for custom errors found in the registered ABIs.
+type RevertError struct {
+	Name   string
+	Reason string
+	Args   []interface{}
+	Data   []byte
+	TxHash common.Hash // set for reverted transactions
+
+	cause error
+}
+
+func (e *RevertError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("execution reverted")
+	if e.Reason != "" {
+		sb.WriteString(": " + e.Reason)
+	}
+	if e.TxHash != (common.Hash{}) {
+		sb.WriteString(" (tx " + e.TxHash.Hex() + ")")
+	}
+	return sb.String()
+}
+
+func (e *RevertError) Unwrap() error {
+	return e.cause
+}
+
+func (e *RevertError) Is(target error) bool {
+	return target == globals.ErrReverted
+}
+
+// decodeRevert turns revert data into a RevertError. Unknown custom errors
+// keep their selector as the reason.
+func decodeRevert(data []byte) *RevertError {
+	revert := &RevertError{Data: data}
+
+	switch {
+	case len(data) == 0:
+		revert.Reason = "no reason"
+	case len(data) < 4:
+		revert.Reason = hexutil.Encode(data)
+	case bytes.Equal(data[:4], errorSelector):
+		revert.Name = "Error"
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			revert.Reason = reason
+		} else {
+			revert.Reason = hexutil.Encode(data)
+		}
+	case bytes.Equal(data[:4], panicSelector):
+		revert.Name = "Panic"
+		code := new(big.Int).SetBytes(data[4:])
+		revert.Reason = fmt.Sprintf("panic 0x%x", code)
+		if reason, ok := panicReasons[code.Uint64()]; ok && code.IsUint64() {
+			revert.Reason += ": " + reason
+		}
+	default:
+		decodeCustomError(revert)
+	}
+
+	return revert
+}
+
+func decodeCustomError(revert *RevertError) {
+	var selector [4]byte
+	copy(selector[:], revert.Data[:4])
+
+	for _, parsed := range registeredABIs() {
+		abiErr, err := parsed.ErrorByID(selector)
+		if err != nil {
+			continue
+		}
+
+		revert.Name = abiErr.Name
+		args, err := abiErr.Inputs.Unpack(revert.Data[4:])
+		if err != nil {
+			revert.Reason = abiErr.Name + "(?)"
+			return
+		}
+		revert.Args = args
+
+		parts := make([]string, 0, len(args))
+		for _, arg := range args {
+			if nested, ok := arg.([]byte); ok && len(nested) >= 4 {
+				// e.g. ExecutionFailed(commandIndex, message) of the universal router
+				parts = append(parts, decodeRevert(nested).Reason)
+				continue
+			}
+			parts = append(parts, fmt.Sprintf("%v", arg))
+		}
+		revert.Reason = abiErr.Name + "(" + strings.Join(parts, ", ") + ")"
+		return
+	}
+
+	revert.Reason = "unknown error " + hexutil.Encode(selector[:])
+}
+
+// asRevertError converts the error of eth_call or eth_estimateGas into a
+// RevertError when the node reports a revert. Other errors are returned as is.
+func asRevertError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var revert *RevertError
+	if errors.As(err, &revert) {
+		return err
+	}
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if hexData, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(hexData); decodeErr == nil {
+				revert = decodeRevert(data)
+				revert.cause = err
+				return revert
+			}
+		}
+	}
+
+	// Some nodes return the reason only in the message.
+	if msg := err.Error(); strings.Contains(msg, "execution reverted") {
+		reason := strings.TrimSpace(strings.TrimPrefix(msg[strings.Index(msg, "execution reverted"):], "execution reverted"))
+		return &RevertError{Reason: strings.TrimPrefix(reason, ": "), cause: err}
+	}
+
+	return err
+}
+
+// replayFailedTx repeats a reverted transaction with eth_call on the state of
+// the block it was mined in, which is the only way to get its revert reason.
+func (c *Client) replayFailedTx(ctx context.Context, chainID *big.Int, tx *types.Transaction, receipt *types.Receipt) error {
+	revert := &RevertError{Reason: "transaction failed", TxHash: tx.Hash(), cause: errTxFailed}
+
+	msg := ethereum.CallMsg{
+		From:      c.senderOf(chainID, tx),
+		To:        tx.To(),
+		Gas:       tx.Gas(),
+		GasFeeCap: tx.GasFeeCap(),
+		GasTipCap: tx.GasTipCap(),
+		Value:     tx.Value(),
+		Data:      tx.Data(),
+	}
+
+	_, err := c.Client.CallContract(ctx, msg, receipt.BlockNumber)
+	if err == nil {
+		// The call passes on the final state of the block, the revert depended
+		// on a transaction mined before this one in the same block.
+		return revert
+	}
+
+	var decoded *RevertError
+	if errors.As(asRevertError(err), &decoded) {
+		decoded.TxHash = tx.Hash()
+		decoded.cause = errTxFailed
+		return decoded
+	}
+
+	return revert
+}
@@ -0,0 +1,33 @@
+package globals
+
+import "errors"
+
+// Error kinds shared by modules, the eth client and the account processing.
+// Errors are wrapped with %w where they happen and checked with errors.Is, so
+// the wording of a message never changes how an error is handled.
+var (
+	// The account cannot pay for the action: token balance or gas.
+	ErrInsufficientBalance = errors.New("insufficient balance")
+
+	// Gas stayed above attention_gwei / attention_tx_cost for max_attention_time.
+	ErrGasTimeout = errors.New("gas wait timeout has been exceeded")
+
+	// No pool exists for the pair and fee tier or the pool is not usable.
+	ErrPoolNotFound = errors.New("pool not found")
+
+	// Every RPC endpoint of the chain answered with a rate limit.
+	ErrRPCRateLimited = errors.New("rpc rate limited")
+
+	// The node rejected the nonce, the local nonce is resynced.
+	ErrNonceTooLow = errors.New("nonce too low")
+
+	// A call or a transaction reverted, see ethClient.RevertError for the reason.
+	ErrReverted = errors.New("execution reverted")
+
+	// The account spent its gas_budget_daily or gas_budget_run on fees.
+	ErrBudgetExceeded = errors.New("gas budget exceeded")
+
+	// The quote of a swap is worse than the pool mid-price by more than
+	// max_price_impact_bps, see dex.PriceImpactError.
+	ErrPriceImpact = errors.New("price impact too high")
+)
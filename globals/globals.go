@@ -68,12 +68,30 @@ var (
 	GorutinesCount int
 
 	AttentionGwei    *big.Int // GWEI have 9 decimals
+	AttentionTxCost  *big.Int // Max total cost of one tx in wei (L2 fee + L1 data fee), nil - no limit
 	AttentionTime    int      // Time in seconds that indicates how often to check the throttle reduction
 	MaxAttentionTime int      //Time in minutes how long the waiting cycle will last at most, after which the execution will continue
 
-	Slippage              = big.NewFloat(0.01) // 1%
-	DefaultDeadlineOffset = 120                // 2 minutes
-	ApproveDeadlineOffset = 3600               // 1 hour
+	// Stuck transactions are rebroadcast with the same nonce and fees raised by
+	// TxFeeBumpPercent every TxReplaceAfter seconds. After TxMaxFeeBumps raises
+	// the nonce is cancelled with a zero-value transfer to the sender itself.
+	TxReplaceAfter   = 60
+	TxFeeBumpPercent = 15 // nodes accept a replacement only if fees grow by at least 10%
+	TxMaxFeeBumps    = 3
+
+	// Fee budgets of one account in wei, nil - no limit. The daily budget counts
+	// the fees of all runs since 00:00 UTC, the run budget only the current run.
+	GasBudgetDaily *big.Int
+	GasBudgetRun   *big.Int
+
+	// DryRun simulates transactions (eth_call + estimateGas) instead of broadcasting them.
+	// State file, statistics and error wallets are not touched in this mode.
+	DryRun bool
+
+	SlippageBps           = 50   // 0.5%, amountOutMinimum = quote * (10000 - SlippageBps) / 10000
+	MaxPriceImpactBps     = 300  // 3%, quote against the pool mid-price net of fees
+	DefaultDeadlineOffset = 120  // 2 minutes
+	ApproveDeadlineOffset = 3600 // 1 hour
 	MaxApprove            = big.NewInt(1e18)
 	Erc20ABI              *abi.ABI
 	MaxUint256            = new(big.Int)
@@ -85,19 +103,9 @@ var (
 	NATIVE = common.Address{}
 	NULL   = common.Address{} // need for minor functions
 
-	// tokens array. Need for create tokens graph in pool algoritm
+	// tokens of the Oku pool graph, every pair of them can be swapped through it
 	Tokens = []common.Address{WETH, USDC, USDT, LISK}
 
-	TokensDecimals = map[common.Address]PoolInfo{
-		common.HexToAddress("0x87D3d9CA455DCc9a3Ba5605D2829d994922DD04F"): PoolInfo{Token0: 6, Token1: 6},
-		common.HexToAddress("0xA211813F9d68F9c33bc9C275e40DfC4027016232"): PoolInfo{Token0: 6, Token1: 6},
-		common.HexToAddress("0xA211813F9d68F9c33bc9C275e40DfC4027016232"): PoolInfo{Token0: 6, Token1: 6},
-		common.HexToAddress("0x49DA5d42091F38fc527b9F9B03C1005aBb6aD818"): PoolInfo{Token0: 18, Token1: 6},
-		common.HexToAddress("0x3A670179BdecE7eB4f570e30Ee9D560f7ff4Fac3"): PoolInfo{Token0: 18, Token1: 6},
-		common.HexToAddress("0x0501f71EED6c1F9c1337823C1a48a1390D16235a"): PoolInfo{Token0: 18, Token1: 18},
-		common.HexToAddress("0xD501d4E381491F64274Cc65fdec32b47264a2422"): PoolInfo{Token0: 18, Token1: 18},
-	}
-
 	DecimalsMap = map[common.Address]int{
 		WETH: 18,
 		LISK: 18,
@@ -64,12 +64,8 @@ var (
 )
 
 var (
-	SwapIn    = []byte{0x00}
-	WrapETH   = []byte{0x0b}
-	UnwrapETH = []byte{0x0c}
+	SwapIn        = []byte{0x00}
+	WrapETH       = []byte{0x0b}
+	UnwrapETH     = []byte{0x0c}
+	Permit2Permit = []byte{0x0a} // PERMIT2_PERMIT: signed PermitSingle for the router
 )
-
-type PoolInfo struct {
-	Token0 int
-	Token1 int
-}
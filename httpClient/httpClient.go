@@ -7,11 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"lisk/logger"
+	"lisk/retry"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
-	"strings"
 	"time"
 
 	"golang.org/x/net/publicsuffix"
@@ -51,16 +50,16 @@ func NewHttpClient(proxyURL string) (*HttpClient, error) {
 	return &HttpClient{Client: client}, nil
 }
 
-func (h *HttpClient) SendJSONRequest(urlRequest, method string, reqBody, respBody interface{}) error {
-	req, err := h.createRequest(urlRequest, method, reqBody)
+func (h *HttpClient) SendJSONRequest(ctx context.Context, urlRequest, method string, reqBody, respBody interface{}) error {
+	req, err := h.createRequest(ctx, urlRequest, method, reqBody)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %v", err)
 	}
 
-	return h.executeWithRetries(req, respBody)
+	return h.executeWithRetries(ctx, req, respBody)
 }
 
-func (h *HttpClient) createRequest(urlRequest, method string, reqBody interface{}) (*http.Request, error) {
+func (h *HttpClient) createRequest(ctx context.Context, urlRequest, method string, reqBody interface{}) (*http.Request, error) {
 	var body io.Reader
 	if reqBody != nil {
 		jsonData, err := json.Marshal(reqBody)
@@ -70,7 +69,7 @@ func (h *HttpClient) createRequest(urlRequest, method string, reqBody interface{
 		body = bytes.NewBuffer(jsonData)
 	}
 
-	req, err := http.NewRequestWithContext(context.Background(), method, urlRequest, body)
+	req, err := http.NewRequestWithContext(ctx, method, urlRequest, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HTTP request: %v", err)
 	}
@@ -82,41 +81,53 @@ func (h *HttpClient) createRequest(urlRequest, method string, reqBody interface{
 	return req, nil
 }
 
-func (h *HttpClient) executeWithRetries(req *http.Request, respBody interface{}) error {
-	const maxRetries = 3
-	const retryDelay = 1500 * time.Millisecond
+func (h *HttpClient) executeWithRetries(ctx context.Context, req *http.Request, respBody interface{}) error {
+	retries, err := retry.HTTP.Do(ctx, req.Method+" "+req.URL.Host+req.URL.Path, func() error {
+		return h.execute(req, respBody)
+	})
+	if err != nil && retries > 0 {
+		return fmt.Errorf("request failed after %d retries: %w", retries, err)
+	}
+	return err
+}
 
-	for attempts := 0; attempts < maxRetries; attempts++ {
-		resp, err := h.Client.Do(req)
+func (h *HttpClient) execute(req *http.Request, respBody interface{}) error {
+	// The body is consumed by every attempt.
+	if req.GetBody != nil {
+		body, err := req.GetBody()
 		if err != nil {
-			if strings.Contains(err.Error(), "unexpected EOF") {
-				logger.GlobalLogger.Warn("Unexpected EOF encountered. Retrying... Attempt %d", attempts+1)
-				time.Sleep(retryDelay)
-				continue
-			}
-			return fmt.Errorf("request error: %v", err)
-		}
-		defer resp.Body.Close()
-
-		if err := h.parseResponse(resp, respBody); err != nil {
-			if resp.StatusCode == http.StatusTooManyRequests {
-				logger.GlobalLogger.Warn("Rate limit reached. Retrying... Attempt %d", attempts+1)
-				time.Sleep(retryDelay)
-				continue
-			}
-			return err
+			return fmt.Errorf("failed to reset request body: %v", err)
 		}
+		req.Body = body
+	}
 
-		return nil
+	resp, err := h.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request error: %w", err)
 	}
+	defer resp.Body.Close()
+
+	return h.parseResponse(resp, respBody)
+}
+
+// StatusError is returned for responses with a status other than 200.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d, body: %s", e.StatusCode, e.Body)
+}
 
-	return fmt.Errorf("request failed after %d retries", maxRetries)
+func (e *StatusError) HTTPStatus() int {
+	return e.StatusCode
 }
 
 func (h *HttpClient) parseResponse(resp *http.Response, respBody interface{}) error {
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body) // Ignoring read error to avoid masking original status code
-		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	reader := io.ReadCloser(resp.Body)
@@ -8,14 +8,14 @@ import (
 )
 
 type SwapPair struct {
-	TokenFrom common.Address
-	TokenTo   common.Address
-	Forced    bool
+	TokenFrom common.Address `json:"token_from"`
+	TokenTo   common.Address `json:"token_to"`
+	Forced    bool           `json:"forced"`
 }
 
 type WrapHistory struct {
-	LastAction globals.ActionType
-	LastAmount *big.Int
+	LastAction globals.ActionType `json:"last_action,omitempty"`
+	LastAmount *big.Int           `json:"last_amount,omitempty"`
 }
 
 type SwapRange struct {
@@ -29,9 +29,9 @@ type WrapRange struct {
 }
 
 type LiquidityState struct {
-	ActionCount               int
-	LastAction                globals.ActionType
-	PendingEnterAfterWithdraw bool
+	ActionCount               int                `json:"action_count"`
+	LastAction                globals.ActionType `json:"last_action,omitempty"`
+	PendingEnterAfterWithdraw bool               `json:"pending_enter_after_withdraw,omitempty"`
 }
 
 type FeePool struct {
@@ -104,6 +104,17 @@ type StatRecord struct {
 	TotalSuccess int
 	TodayDate    int // YYYYMMDD
 	TodaySuccess int
+	Retries      int
+	GasUsed      uint64
+	FeesWei      *big.Int // L2 execution plus L1 data fees
+	FailedTxs    int
+}
+
+// GasSpend sums the receipts of the transactions sent for one module.
+type GasSpend struct {
+	GasUsed   uint64
+	Fees      *big.Int
+	FailedTxs int
 }
 
 type BlockscoutResp struct {
@@ -1,10 +1,12 @@
 package balanceChecker
 
 import (
+	"context"
 	"fmt"
 	"lisk/account"
 	"lisk/ethClient"
 	"lisk/globals"
+	"lisk/logger"
 	"lisk/utils"
 	"math/big"
 
@@ -27,26 +29,53 @@ func NewChecker(client *ethClient.Client, tokens map[string]common.Address) (*Ch
 	}, nil
 }
 
-func (c *Checker) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error {
-	balances := make(map[string]map[string]string)
+func (c *Checker) Action(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error {
+	return c.BatchAction(ctx, []*account.Account{acc})
+}
 
-	for token, address := range c.Tokens {
-		balance, err := c.Client.BalanceCheck(acc.Address, address)
-		if err != nil {
-			return fmt.Errorf("failed to check balance for token %s: %w", token, err)
-		}
+// BatchAction reads the balances of all accounts through Multicall3 and
+// writes the whole table at once.
+func (c *Checker) BatchAction(ctx context.Context, accs []*account.Account) error {
+	owners := make([]common.Address, 0, len(accs))
+	for _, acc := range accs {
+		owners = append(owners, acc.Address)
+	}
 
-		result := utils.ConvertFromWei(balance, globals.DecimalsMap[address])
-		if _, exists := balances[acc.Address.Hex()]; !exists {
-			balances[acc.Address.Hex()] = make(map[string]string)
-		}
+	tokens := make([]common.Address, 0, len(c.Tokens))
+	for _, address := range c.Tokens {
+		tokens = append(tokens, address)
+	}
 
-		balances[acc.Address.Hex()][token] = result
+	decimals, err := c.Client.BatchDecimals(ctx, tokens)
+	if err != nil {
+		return fmt.Errorf("failed to read token decimals: %w", err)
+	}
+
+	result, failed, err := c.Client.BatchBalances(ctx, owners, tokens)
+	if err != nil {
+		return fmt.Errorf("failed to check balances: %w", err)
+	}
+	for _, err := range failed {
+		logger.GlobalLogger.Warnf("Balance check: %v", err)
+	}
+
+	balances := make(map[string]map[string]string, len(owners))
+	for _, owner := range owners {
+		balances[owner.Hex()] = make(map[string]string, len(c.Tokens))
+		for token, address := range c.Tokens {
+			key := ethClient.BalanceKey{Owner: owner, Token: address}
+			if _, isFailed := failed[key]; isFailed {
+				balances[owner.Hex()][token] = "error"
+				continue
+			}
+			balances[owner.Hex()][token] = utils.ConvertFromWei(result[key], decimals[address])
+		}
 	}
 
 	if err := utils.WriteAddrTokenBalancesToFile(balances); err != nil {
 		return fmt.Errorf("failed to write balances to file: %w", err)
 	}
 
+	logger.GlobalLogger.Infof("Balances of %d accounts written to %s", len(accs), utils.GetPath("balances"))
 	return nil
 }
@@ -1,6 +1,7 @@
 package modules
 
 import (
+	"context"
 	"lisk/account"
 	"lisk/globals"
 	"math/big"
@@ -11,5 +12,11 @@ import (
 type ModulesFasad interface {
 	// Action(account *account.Account, amountKey string) error
 	// ExecuteHardcodedTransaction(acc *account.Account) error
-	Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error
+	Action(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error
+}
+
+// BatchModule is implemented by read-only modules that can serve all accounts
+// of a run at once instead of one action per account.
+type BatchModule interface {
+	BatchAction(ctx context.Context, accs []*account.Account) error
 }
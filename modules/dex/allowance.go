@@ -1,8 +1,10 @@
 package dex
 
 import (
+	"context"
 	"fmt"
 	"lisk/account"
+	"lisk/ethClient"
 	"lisk/globals"
 	"lisk/logger"
 	"math/big"
@@ -11,25 +13,30 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-func (d *Dex) ensureAllowance(token common.Address, amount *big.Int, acc *account.Account) error {
-	if err := d.ensurePermitAllowance(token, amount, acc); err != nil {
-		return fmt.Errorf("permit allowance check failed: %w", err)
+// ensureAllowance makes sure the Universal Router can take amount of token
+// through Permit2. It returns the input of a PERMIT2_PERMIT command when the
+// router allowance is granted with a signature inside the swap, nil when no
+// command is needed.
+func (d *Dex) ensureAllowance(ctx context.Context, token common.Address, amount *big.Int, acc *account.Account) ([]byte, error) {
+	if err := d.ensurePermitAllowance(ctx, token, amount, acc); err != nil {
+		return nil, fmt.Errorf("permit allowance check failed: %w", err)
 	}
 
-	if err := d.ensureRouterAllowance(token, amount, acc); err != nil {
-		return fmt.Errorf("router allowance check failed: %w", err)
+	permit, err := d.ensureRouterAllowance(ctx, token, amount, acc)
+	if err != nil {
+		return nil, fmt.Errorf("router allowance check failed: %w", err)
 	}
 
-	return nil
+	return permit, nil
 }
 
-func (d *Dex) ensurePermitAllowance(token common.Address, amount *big.Int, acc *account.Account) error {
+func (d *Dex) ensurePermitAllowance(ctx context.Context, token common.Address, amount *big.Int, acc *account.Account) error {
 	data, err := globals.Erc20ABI.Pack("allowance", acc.Address, d.PermitCA)
 	if err != nil {
 		return fmt.Errorf("failed to pack permit allowance data: %w", err)
 	}
 
-	result, err := d.Client.CallCA(token, data)
+	result, err := d.Client.CallCA(ctx, token, data)
 	if err != nil {
 		return fmt.Errorf("permit allowance call failed: %w", err)
 	}
@@ -45,7 +52,7 @@ func (d *Dex) ensurePermitAllowance(token common.Address, amount *big.Int, acc *
 	}
 
 	if permitAllowance.Cmp(amount) < 0 {
-		if _, err := d.Client.ApproveTx(token, d.PermitCA, acc, globals.MaxApprove, false); err != nil {
+		if _, err := d.Client.ApproveTx(ctx, token, d.PermitCA, acc, globals.MaxApprove, false); err != nil {
 			return fmt.Errorf("failed to approve via permit: %w", err)
 		}
 	}
@@ -53,53 +60,67 @@ func (d *Dex) ensurePermitAllowance(token common.Address, amount *big.Int, acc *
 	return nil
 }
 
-func (d *Dex) ensureRouterAllowance(token common.Address, amount *big.Int, acc *account.Account) error {
+// ensureRouterAllowance checks the Permit2 allowance of the router. A missing
+// or expired allowance is granted with a signed permit; signers that cannot
+// sign EIP-712 messages fall back to an on-chain Permit2 approve.
+func (d *Dex) ensureRouterAllowance(ctx context.Context, token common.Address, amount *big.Int, acc *account.Account) ([]byte, error) {
 	data, err := d.ABI.Pack("allowance", acc.Address, token, d.UniversalCA)
 	if err != nil {
-		return fmt.Errorf("failed to pack router allowance data: %w", err)
+		return nil, fmt.Errorf("failed to pack router allowance data: %w", err)
 	}
 
-	result, err := d.Client.CallCA(d.PermitCA, data)
+	result, err := d.Client.CallCA(ctx, d.PermitCA, data)
 	if err != nil {
-		return fmt.Errorf("router allowance call failed: %w", err)
+		return nil, fmt.Errorf("router allowance call failed: %w", err)
 	}
 
 	unpackedData, err := d.ABI.Methods["allowance"].Outputs.Unpack(result)
 	if err != nil {
-		return fmt.Errorf("failed to unpack router allowance data: %w", err)
+		return nil, fmt.Errorf("failed to unpack router allowance data: %w", err)
 	}
 
-	if len(unpackedData) < 2 {
-		return fmt.Errorf("unexpected result: insufficient data for router allowance")
+	if len(unpackedData) < 3 {
+		return nil, fmt.Errorf("unexpected result: insufficient data for router allowance")
 	}
 
 	routerAllowance, ok := unpackedData[0].(*big.Int)
 	if !ok {
-		return fmt.Errorf("unexpected type for router allowance")
+		return nil, fmt.Errorf("unexpected type for router allowance")
 	}
 
 	expiration, ok := unpackedData[1].(*big.Int)
 	if !ok {
-		return fmt.Errorf("unexpected type for expiration")
+		return nil, fmt.Errorf("unexpected type for expiration")
+	}
+
+	nonce, ok := unpackedData[2].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type for permit nonce")
 	}
 
 	currentTime := big.NewInt(time.Now().Unix())
-	if routerAllowance.Cmp(amount) < 0 || expiration.Cmp(currentTime) <= 0 {
-		logger.GlobalLogger.Infof("Router allowance insufficient or expired. Initiating approval...")
-		if err := d.approveToken(token, acc); err != nil {
-			return fmt.Errorf("failed to approve router allowance: %w", err)
-		}
-		time.Sleep(20 * time.Second)
+	if routerAllowance.Cmp(amount) >= 0 && expiration.Cmp(currentTime) > 0 {
+		return nil, nil
 	}
 
-	return nil
+	permit, err := d.signPermit(ctx, token, amount, nonce, acc)
+	if err == nil {
+		logger.GlobalLogger.Infof("[%v] Router allowance insufficient or expired. Signed a Permit2 permit for the swap", acc.Address.Hex())
+		return permit, nil
+	}
+
+	logger.GlobalLogger.Warnf("[%v] Permit2 signature is not available (%v). Approving on chain...", acc.Address.Hex(), err)
+	if err := d.approveToken(ctx, token, acc); err != nil {
+		return nil, fmt.Errorf("failed to approve router allowance: %w", err)
+	}
+	return nil, nil
 }
 
-func (d *Dex) approveToken(token common.Address, acc *account.Account) error {
+func (d *Dex) approveToken(ctx context.Context, token common.Address, acc *account.Account) error {
 	deadline := big.NewInt(time.Now().Unix() + int64(globals.ApproveDeadlineOffset))
 	data, err := d.ABI.Pack("approve", token, d.UniversalCA, globals.MaxApprove, deadline)
 	if err != nil {
 		return fmt.Errorf("failed to pack approve data: %w", err)
 	}
-	return d.Client.SendTransaction(acc.PrivateKey, acc.Address, d.PermitCA, d.Client.GetNonce(acc.Address), big.NewInt(0), data)
+	return d.Client.SendTransaction(ethClient.WithApproval(ctx), acc.Signer, d.PermitCA, big.NewInt(0), data)
 }
@@ -4,28 +4,12 @@ import (
 	"fmt"
 	"lisk/account"
 	"lisk/ethClient"
-	"lisk/globals"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 )
 
-func (d *Dex) buildTxData(tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, fee *big.Int) ([]byte, [][]byte, error) {
-	totalFee, err := d.fetchPool(tokenIn, tokenOut, fee)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	pathBytes, err := d.encodeV3Path(tokenIn, totalFee.Fee, tokenOut)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	amountOutMin, err := d.getAmountMin(pathBytes, amountIn)
-	if err != nil {
-		return nil, nil, err
-	}
-
+func (d *Dex) buildTxData(tokenIn, tokenOut common.Address, amountIn, amountOutMin *big.Int, acc *account.Account, pathBytes []byte) ([]byte, [][]byte, error) {
 	switch {
 	case ethClient.IsNativeToken(tokenIn):
 		swapData, err := d.packSwapData(acc.Address, amountIn, amountOutMin, pathBytes, false)
@@ -46,7 +30,8 @@ func (d *Dex) buildTxData(tokenIn, tokenOut common.Address, amountIn *big.Int, a
 		if err != nil {
 			return nil, nil, fmt.Errorf("packSwapData failed: %w", err)
 		}
-		unwrapEncoded, err := d.packWrapETHData(acc.Address, amountIn)
+		// UNWRAP_WETH takes a minimum too, the WETH output of the swap.
+		unwrapEncoded, err := d.packWrapETHData(acc.Address, amountOutMin)
 		if err != nil {
 			return nil, nil, fmt.Errorf("packWrapETHData failed: %w", err)
 		}
@@ -66,36 +51,9 @@ func (d *Dex) buildTxData(tokenIn, tokenOut common.Address, amountIn *big.Int, a
 	}
 }
 
-func (d *Dex) getAmountMin(path []byte, amountIn *big.Int) (*big.Int, error) {
-	data, err := d.ABI.Pack("quoteExactInput", path, amountIn)
-	if err != nil {
-		return nil, fmt.Errorf("failed to pack ABI data: %w", err)
-	}
-
-	response, err := d.Client.CallCA(d.Quoter, data)
-	if err != nil {
-		return nil, fmt.Errorf("call to Quoter failed: %w", err)
-	}
-	if len(response) == 0 {
-		return nil, fmt.Errorf("empty response from contract call")
-	}
-
-	unpackedData, err := d.ABI.Unpack("quoteExactInput", response)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unpack ABI data: %w", err)
-	}
-
-	amountMinOut, ok := unpackedData[0].(*big.Int)
-	if !ok {
-		return nil, fmt.Errorf("error of conversion to *big.Int")
-	}
-
-	return applySlippage(amountMinOut, globals.Slippage), nil
-}
-
-func applySlippage(amount *big.Int, slippage *big.Float) *big.Int {
-	amountFloat := new(big.Float).SetInt(amount)
-	adjustedAmountFloat := new(big.Float).Mul(amountFloat, slippage)
-	adjustedAmount, _ := adjustedAmountFloat.Int(nil)
-	return adjustedAmount
+// applySlippage returns the minimum output for a quote: amount reduced by
+// slippageBps basis points.
+func applySlippage(amount *big.Int, slippageBps int) *big.Int {
+	adjusted := new(big.Int).Mul(amount, big.NewInt(int64(10000-slippageBps)))
+	return adjusted.Quo(adjusted, big.NewInt(10000))
 }
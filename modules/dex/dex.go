@@ -1,12 +1,16 @@
 package dex
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"lisk/account"
 	"lisk/ethClient"
 	"lisk/globals"
+	"lisk/logger"
+	"lisk/utils"
 	"math/big"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -21,6 +25,8 @@ type Dex struct {
 	Quoter      common.Address
 	Fees        []*big.Int // например 3000 (0.3%)
 	Client      *ethClient.Client
+	graph       graphCache
+	pools       *poolMetaCache
 }
 
 func NewDex(addresses map[string]string, univAbi *abi.ABI, client *ethClient.Client) (*Dex, error) {
@@ -58,55 +64,84 @@ func NewDex(addresses map[string]string, univAbi *abi.ABI, client *ethClient.Cli
 		Quoter:      quoterCA,
 		Fees:        fees,
 		Client:      client,
+		pools:       loadPoolMetaCache(utils.GetPath("pools")),
 	}, nil
 }
 
-func (d *Dex) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, actionType globals.ActionType) error {
-	data, value, err := d.createTransaction(tokenIn, tokenOut, amountIn, acc)
+func (d *Dex) Action(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, actionType globals.ActionType) error {
+	call, details, err := d.createTransaction(ctx, tokenIn, tokenOut, amountIn, acc)
 	if err != nil {
 		return err
 	}
 
 	if !ethClient.IsNativeToken(tokenIn) {
-		if err := d.ensureAllowance(tokenIn, amountIn, acc); err != nil {
+		permit, err := d.ensureAllowance(ctx, tokenIn, amountIn, acc)
+		if err != nil {
 			return fmt.Errorf("failed to approve tokens: %w", err)
 		}
+		if permit != nil {
+			call.prepend(globals.Permit2Permit, permit)
+			details["permit2"] = "signature"
+		}
+	}
+
+	data, err := d.packExecute(call)
+	if err != nil {
+		return err
 	}
 
 	return d.Client.SendTransaction(
-		acc.PrivateKey,
-		acc.Address,
+		ethClient.WithTxDetails(ctx, details),
+		acc.Signer,
 		d.UniversalCA,
-		d.Client.GetNonce(acc.Address),
-		value,
+		call.Value,
 		data,
 	)
 }
 
-func (d *Dex) createTransaction(tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account) ([]byte, *big.Int, error) {
-	var (
-		localFees = append([]*big.Int{}, d.Fees...)
-		commands  []byte
-		inputs    [][]byte
-		err       error
-	)
+// executeCall is a list of Universal Router commands with their inputs.
+type executeCall struct {
+	Commands []byte
+	Inputs   [][]byte
+	Value    *big.Int
+}
 
-	for i := 0; i < len(localFees); i++ {
-		fee := localFees[i]
-		commands, inputs, err = d.buildTxData(tokenIn, tokenOut, amountIn, acc, fee)
-		if err != nil {
-			if verifyError(err) {
-				localFees = removeFee(localFees, i)
-				i--
-				continue
-			}
-			return nil, nil, fmt.Errorf("error building transaction data: %w", err)
-		}
+// prepend puts a command in front of the others, e.g. a Permit2 permit that
+// has to be applied before the swap.
+func (c *executeCall) prepend(command []byte, input []byte) {
+	c.Commands = append(append([]byte{}, command...), c.Commands...)
+	c.Inputs = append([][]byte{input}, c.Inputs...)
+}
+
+func (d *Dex) packExecute(call *executeCall) ([]byte, error) {
+	deadline := big.NewInt(time.Now().Unix() + int64(globals.DefaultDeadlineOffset))
+	data, err := d.ABI.Pack("execute", call.Commands, call.Inputs, deadline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack universalRouter.execute: %w", err)
 	}
-	deadline := big.NewInt(time.Now().Unix() + int64(globals.DefaultDeadlineOffset)) // 20 min
-	data, err := d.ABI.Pack("execute", commands, inputs, deadline)
+	return data, nil
+}
+
+// createTransaction builds the commands of the execute call for the best
+// quoted route. The returned details describe the choice for the transaction
+// journal.
+func (d *Dex) createTransaction(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account) (*executeCall, map[string]string, error) {
+	quote, quoted, err := d.bestRoute(ctx, tokenIn, tokenOut, amountIn)
+	if err != nil {
+		return nil, nil, err
+	}
+	impactBps, err := d.checkPriceImpact(ctx, quote, amountIn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	amountOutMin := applySlippage(quote.AmountOut, globals.SlippageBps)
+	logger.GlobalLogger.Infof("[%v] Oku route %s: quote %v, gas %d (~%v of the output), price impact %.2f%%, minimum %v, best of %d quoted routes",
+		acc.Address.Hex(), quote.Route, quote.AmountOut, quote.GasEstimate, quote.GasCost, float64(impactBps)/100, amountOutMin, quoted)
+
+	commands, inputs, err := d.buildTxData(tokenIn, tokenOut, amountIn, amountOutMin, acc, quote.Path)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to pack universalRouter.execute: %w", err)
+		return nil, nil, fmt.Errorf("error building transaction data: %w", err)
 	}
 
 	value := big.NewInt(0)
@@ -114,30 +149,23 @@ func (d *Dex) createTransaction(tokenIn, tokenOut common.Address, amountIn *big.
 		value = amountIn
 	}
 
-	return data, value, nil
-}
-
-func removeFee(fees []*big.Int, index int) []*big.Int {
-	return append(fees[:index], fees[index+1:]...)
+	details := map[string]string{
+		"route":          quote.Route.String(),
+		"fee_tiers":      quote.Route.feeTiers(),
+		"amount_in":      amountIn.String(),
+		"quote":          quote.AmountOut.String(),
+		"quote_gas":      strconv.FormatUint(quote.GasEstimate, 10),
+		"amount_out_min": amountOutMin.String(),
+		"price_impact":   strconv.FormatInt(impactBps, 10),
+	}
+	return &executeCall{Commands: commands, Inputs: inputs, Value: value}, details, nil
 }
 
 func verifyError(err error) bool {
 	if err == nil {
 		return false
 	}
-	errMsg := err.Error()
-	errorSubstrings := []string{
-		"no pool found for tokens",
-		"invalid price calculated",
-		"invalid pool address returned",
-		"call to Quoter failed: execution reverted",
-		// "Gas wait timeout has been exceeded",
-	}
 
-	for _, substr := range errorSubstrings {
-		if strings.Contains(errMsg, substr) {
-			return true
-		}
-	}
-	return false
+	// A reverted quote means the pool of this fee tier cannot serve the swap.
+	return errors.Is(err, globals.ErrPoolNotFound) || errors.Is(err, globals.ErrReverted)
 }
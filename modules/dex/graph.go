@@ -0,0 +1,207 @@
+package dex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lisk/globals"
+	"lisk/logger"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	maxRouteHops = 3
+	graphTTL     = time.Hour // new pools are picked up after this time
+	// graphRetryTTL is used instead of graphTTL when some pools could not be
+	// read, so they are probed again soon.
+	graphRetryTTL = time.Minute
+)
+
+// poolEdge is one pool of the graph, seen from the token it is entered with.
+type poolEdge struct {
+	TokenOut common.Address
+	Fee      *big.Int
+	Pool     common.Address
+}
+
+// poolGraph holds the existing pools between globals.Tokens for every fee
+// tier: token -> pools it can be swapped through.
+type poolGraph map[common.Address][]poolEdge
+
+// swapRoute is a V3 path: Pools[i] with fee Fees[i] swaps Tokens[i] for
+// Tokens[i+1].
+type swapRoute struct {
+	Tokens []common.Address
+	Fees   []*big.Int
+	Pools  []common.Address
+}
+
+type graphCache struct {
+	mu        sync.Mutex
+	graph     poolGraph
+	expiresAt time.Time
+}
+
+// poolGraph returns the cached graph and rebuilds it from the factory when it
+// has expired.
+func (d *Dex) poolGraph(ctx context.Context) (poolGraph, error) {
+	d.graph.mu.Lock()
+	defer d.graph.mu.Unlock()
+
+	if d.graph.graph != nil && time.Now().Before(d.graph.expiresAt) {
+		return d.graph.graph, nil
+	}
+
+	graph, failed, err := d.buildGraph(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := graphTTL
+	if failed > 0 {
+		ttl = graphRetryTTL
+	}
+	d.graph.graph = graph
+	d.graph.expiresAt = time.Now().Add(ttl)
+	return graph, nil
+}
+
+// buildGraph probes every pair of globals.Tokens for every fee tier in
+// parallel. A pool that cannot be read is left out of the graph and counted
+// in failed; the build fails only when no pool could be read at all.
+func (d *Dex) buildGraph(ctx context.Context) (poolGraph, int, error) {
+	type probe struct {
+		tokenA, tokenB common.Address
+		fee            *big.Int
+		pool           common.Address
+		found          bool
+		err            error
+	}
+
+	var probes []*probe
+	for i, tokenA := range globals.Tokens {
+		for _, tokenB := range globals.Tokens[i+1:] {
+			for _, fee := range d.Fees {
+				probes = append(probes, &probe{tokenA: tokenA, tokenB: tokenB, fee: fee})
+			}
+		}
+	}
+
+	var g errgroup.Group
+	g.SetLimit(maxParallelQuotes)
+	for _, p := range probes {
+		g.Go(func() error {
+			pool, err := d.fetchPool(ctx, p.tokenA, p.tokenB, p.fee)
+			switch {
+			case err == nil:
+				p.pool, p.found = pool.PoolAddress, true
+			case !errors.Is(err, globals.ErrPoolNotFound):
+				p.err = err
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	graph := make(poolGraph)
+	pools, failed := 0, 0
+	var lastErr error
+	for _, p := range probes {
+		if p.err != nil {
+			logger.GlobalLogger.Warnf("[Oku] Pool %s/%s fee %v skipped: %v", tokenSymbol(p.tokenA), tokenSymbol(p.tokenB), p.fee, p.err)
+			failed++
+			lastErr = p.err
+			continue
+		}
+		if !p.found {
+			continue
+		}
+
+		graph[p.tokenA] = append(graph[p.tokenA], poolEdge{TokenOut: p.tokenB, Fee: p.fee, Pool: p.pool})
+		graph[p.tokenB] = append(graph[p.tokenB], poolEdge{TokenOut: p.tokenA, Fee: p.fee, Pool: p.pool})
+		pools++
+	}
+
+	if pools == 0 && failed > 0 {
+		return nil, failed, fmt.Errorf("failed to build pool graph: %w", lastErr)
+	}
+
+	logger.GlobalLogger.Infof("[Oku] Pool graph: %d pools between %d tokens (%d failed)", pools, len(globals.Tokens), failed)
+	return graph, failed, nil
+}
+
+// routes enumerates every path from tokenIn to tokenOut with at most
+// maxRouteHops pools that does not visit a token twice.
+func (g poolGraph) routes(tokenIn, tokenOut common.Address) []swapRoute {
+	var (
+		result  []swapRoute
+		tokens  = []common.Address{tokenIn}
+		fees    []*big.Int
+		pools   []common.Address
+		visited = map[common.Address]bool{tokenIn: true}
+		walk    func(token common.Address)
+	)
+
+	walk = func(token common.Address) {
+		for _, edge := range g[token] {
+			if visited[edge.TokenOut] {
+				continue
+			}
+
+			tokens = append(tokens, edge.TokenOut)
+			fees = append(fees, edge.Fee)
+			pools = append(pools, edge.Pool)
+
+			if edge.TokenOut == tokenOut {
+				result = append(result, swapRoute{
+					Tokens: append([]common.Address{}, tokens...),
+					Fees:   append([]*big.Int{}, fees...),
+					Pools:  append([]common.Address{}, pools...),
+				})
+			} else if len(fees) < maxRouteHops {
+				visited[edge.TokenOut] = true
+				walk(edge.TokenOut)
+				visited[edge.TokenOut] = false
+			}
+
+			tokens = tokens[:len(tokens)-1]
+			fees = fees[:len(fees)-1]
+			pools = pools[:len(pools)-1]
+		}
+	}
+	walk(tokenIn)
+
+	return result
+}
+
+func (r swapRoute) String() string {
+	s := tokenSymbol(r.Tokens[0])
+	for i, fee := range r.Fees {
+		s += fmt.Sprintf(" -(%v)-> %s", fee, tokenSymbol(r.Tokens[i+1]))
+	}
+	return s
+}
+
+func tokenSymbol(token common.Address) string {
+	switch token {
+	case globals.WETH:
+		return "WETH"
+	case globals.USDC:
+		return "USDC"
+	case globals.USDT:
+		return "USDT"
+	case globals.LISK:
+		return "LISK"
+	default:
+		return token.Hex()
+	}
+}
@@ -28,19 +28,25 @@ func (d *Dex) packWrapETHData(recipient common.Address, amountIn *big.Int) ([]by
 	return args.Pack(recipient, amountIn)
 }
 
-func (d *Dex) encodeV3Path(tokenIn common.Address, fee *big.Int, tokenOut common.Address) ([]byte, error) {
-	if fee.Cmp(big.NewInt(0xFFFFFF)) > 0 {
-		return nil, fmt.Errorf("fee exceeds 24 bits")
+// encodeV3Path packs a route as token (20 bytes) | fee (3 bytes) | token ...
+func (d *Dex) encodeV3Path(route swapRoute) ([]byte, error) {
+	if len(route.Tokens) != len(route.Fees)+1 || len(route.Fees) == 0 {
+		return nil, fmt.Errorf("invalid path: %d tokens for %d fees", len(route.Tokens), len(route.Fees))
 	}
-	feeUint32 := uint32(fee.Uint64())
 
-	path := make([]byte, 0, 20+3+20)
-	path = append(path, tokenIn.Bytes()...)
+	path := make([]byte, 0, 20+len(route.Fees)*(3+20))
+	path = append(path, route.Tokens[0].Bytes()...)
 
-	feeBytes := make([]byte, 4)
-	binary.BigEndian.PutUint32(feeBytes, feeUint32)
+	for i, fee := range route.Fees {
+		if fee.Cmp(big.NewInt(0xFFFFFF)) > 0 {
+			return nil, fmt.Errorf("fee exceeds 24 bits")
+		}
 
-	path = append(path, feeBytes[1:4]...)
-	path = append(path, tokenOut.Bytes()...)
+		feeBytes := make([]byte, 4)
+		binary.BigEndian.PutUint32(feeBytes, uint32(fee.Uint64()))
+
+		path = append(path, feeBytes[1:4]...)
+		path = append(path, route.Tokens[i+1].Bytes()...)
+	}
 	return path, nil
 }
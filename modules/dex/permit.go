@@ -0,0 +1,118 @@
+package dex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lisk/account"
+	"lisk/globals"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// permitExpiration is how long a signed router allowance stays valid. Swaps
+// in this time need no new permit.
+const permitExpiration = 30 * 24 * time.Hour
+
+var (
+	errPermitUnsupported = errors.New("signer cannot sign typed data")
+
+	// maxPermitAmount is the largest Permit2 allowance, type(uint160).max.
+	maxPermitAmount = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 160), big.NewInt(1))
+)
+
+// permitTypes are the EIP-712 types of Permit2 PermitSingle.
+var permitTypes = apitypes.Types{
+	"EIP712Domain": {
+		{Name: "name", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	},
+	"PermitSingle": {
+		{Name: "details", Type: "PermitDetails"},
+		{Name: "spender", Type: "address"},
+		{Name: "sigDeadline", Type: "uint256"},
+	},
+	"PermitDetails": {
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint160"},
+		{Name: "expiration", Type: "uint48"},
+		{Name: "nonce", Type: "uint48"},
+	},
+}
+
+// signPermit signs a Permit2 PermitSingle that lets the router spend any
+// amount of token for permitExpiration and returns the input of the PERMIT2_PERMIT
+// command: abi.encode(PermitSingle, signature).
+func (d *Dex) signPermit(ctx context.Context, token common.Address, amount, nonce *big.Int, acc *account.Account) ([]byte, error) {
+	signer, ok := acc.Signer.(account.TypedDataSigner)
+	if !ok {
+		return nil, errPermitUnsupported
+	}
+
+	chainID, err := d.Client.GetChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if amount.Cmp(maxPermitAmount) > 0 {
+		return nil, fmt.Errorf("amount %v exceeds the Permit2 allowance limit", amount)
+	}
+	now := time.Now()
+	permitAmount := maxPermitAmount
+	expiration := big.NewInt(now.Add(permitExpiration).Unix())
+	// The signature itself is only accepted shortly, the allowance lasts.
+	sigDeadline := big.NewInt(now.Unix() + int64(globals.ApproveDeadlineOffset))
+
+	typedData := apitypes.TypedData{
+		Types:       permitTypes,
+		PrimaryType: "PermitSingle",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Permit2",
+			ChainId:           math.NewHexOrDecimal256(chainID),
+			VerifyingContract: d.PermitCA.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"details": map[string]interface{}{
+				"token":      token.Hex(),
+				"amount":     permitAmount.String(),
+				"expiration": expiration.String(),
+				"nonce":      nonce.String(),
+			},
+			"spender":     d.UniversalCA.Hex(),
+			"sigDeadline": sigDeadline.String(),
+		},
+	}
+
+	signature, err := signer.SignTypedData(ctx, typedData)
+	if err != nil {
+		return nil, err
+	}
+
+	return packPermitData(token, permitAmount, expiration, nonce, d.UniversalCA, sigDeadline, signature)
+}
+
+// packPermitData encodes the input of PERMIT2_PERMIT. PermitSingle is a
+// static tuple, so its fields are encoded in place.
+func packPermitData(token common.Address, amount, expiration, nonce *big.Int, spender common.Address, sigDeadline *big.Int, signature []byte) ([]byte, error) {
+	args := abi.Arguments{
+		{Type: abi.Type{T: abi.AddressTy}},         // details.token
+		{Type: abi.Type{T: abi.UintTy, Size: 160}}, // details.amount
+		{Type: abi.Type{T: abi.UintTy, Size: 48}},  // details.expiration
+		{Type: abi.Type{T: abi.UintTy, Size: 48}},  // details.nonce
+		{Type: abi.Type{T: abi.AddressTy}},         // spender
+		{Type: abi.Type{T: abi.UintTy, Size: 256}}, // sigDeadline
+		{Type: abi.Type{T: abi.BytesTy}},           // signature
+	}
+
+	data, err := args.Pack(token, amount, expiration, nonce, spender, sigDeadline, signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack permit data: %w", err)
+	}
+	return data, nil
+}
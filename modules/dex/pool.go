@@ -1,6 +1,7 @@
 package dex
 
 import (
+	"context"
 	"fmt"
 	"lisk/globals"
 	"lisk/models"
@@ -10,13 +11,13 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
-func (d *Dex) fetchPool(tokenIn, tokenOut common.Address, fee *big.Int) (*models.FeePool, error) {
+func (d *Dex) fetchPool(ctx context.Context, tokenIn, tokenOut common.Address, fee *big.Int) (*models.FeePool, error) {
 	data, err := d.ABI.Pack("getPool", tokenIn, tokenOut, fee)
 	if err != nil {
 		return nil, fmt.Errorf("failed to pack getPool data: %w", err)
 	}
 
-	result, err := d.Client.CallCA(d.Factory, data)
+	result, err := d.Client.CallCA(ctx, d.Factory, data)
 	if err != nil {
 		return nil, fmt.Errorf("getPool call failed: %w", err)
 	}
@@ -28,15 +29,43 @@ func (d *Dex) fetchPool(tokenIn, tokenOut common.Address, fee *big.Int) (*models
 
 	poolAddress, ok := unpacked[0].(common.Address)
 	if !ok || poolAddress == (common.Address{}) {
-		return nil, fmt.Errorf("invalid pool address returned")
+		return nil, fmt.Errorf("%w: %s/%s fee %v", globals.ErrPoolNotFound, tokenIn.Hex(), tokenOut.Hex(), fee)
 	}
 
-	data, err = d.ABI.Pack("slot0")
+	sqrtPriceX96, err := d.sqrtPriceX96(ctx, poolAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := d.poolMeta(ctx, poolAddress)
+	if err != nil {
+		return nil, err
+	}
+	if meta.Fee != fee.Uint64() || !meta.hasPair(tokenIn, tokenOut) {
+		return nil, fmt.Errorf("%w: pool %s is %s/%s fee %d, expected %s/%s fee %v", globals.ErrPoolNotFound,
+			poolAddress.Hex(), meta.Token0.Hex(), meta.Token1.Hex(), meta.Fee, tokenIn.Hex(), tokenOut.Hex(), fee)
+	}
+
+	price := meta.price(sqrtPriceX96, tokenIn)
+	if price <= 0 || math.IsInf(price, 0) {
+		return nil, fmt.Errorf("%w: pool %s is not initialised", globals.ErrPoolNotFound, poolAddress.Hex())
+	}
+
+	return &models.FeePool{
+		Fee:          fee,
+		PoolAddress:  poolAddress,
+		SqrtPriceX96: sqrtPriceX96,
+	}, nil
+}
+
+// sqrtPriceX96 reads the current price of the pool from slot0.
+func (d *Dex) sqrtPriceX96(ctx context.Context, pool common.Address) (*big.Int, error) {
+	data, err := d.ABI.Pack("slot0")
 	if err != nil {
 		return nil, fmt.Errorf("failed to pack slot0 data: %w", err)
 	}
 
-	result, err = d.Client.CallCA(poolAddress, data)
+	result, err := d.Client.CallCA(ctx, pool, data)
 	if err != nil {
 		return nil, fmt.Errorf("slot0 call failed: %w", err)
 	}
@@ -50,31 +79,37 @@ func (d *Dex) fetchPool(tokenIn, tokenOut common.Address, fee *big.Int) (*models
 	if !ok {
 		return nil, fmt.Errorf("invalid sqrtPriceX96 type")
 	}
+	return sqrtPriceX96, nil
+}
 
-	price := calculatePrice(
-		sqrtPriceX96,
-		globals.TokensDecimals[poolAddress].Token0,
-		globals.TokensDecimals[poolAddress].Token1,
-	)
+func (m PoolMeta) hasPair(tokenA, tokenB common.Address) bool {
+	return (m.Token0 == tokenA && m.Token1 == tokenB) || (m.Token0 == tokenB && m.Token1 == tokenA)
+}
 
-	if price > 1.1 {
-		return nil, fmt.Errorf("invalid price calculated: %f", price)
-	}
+// zeroForOne tells if tokenIn is token0, i.e. the swap sells token0.
+func (m PoolMeta) zeroForOne(tokenIn common.Address) bool {
+	return tokenIn == m.Token0
+}
 
-	return &models.FeePool{
-		Fee:          fee,
-		PoolAddress:  poolAddress,
-		SqrtPriceX96: sqrtPriceX96,
-	}, nil
+// price returns how many tokenOut one tokenIn is worth at sqrtPriceX96, in
+// whole tokens.
+func (m PoolMeta) price(sqrtPriceX96 *big.Int, tokenIn common.Address) float64 {
+	price := calculatePrice(sqrtPriceX96, m.Decimals0, m.Decimals1)
+	if !m.zeroForOne(tokenIn) {
+		return 1 / price
+	}
+	return price
 }
 
+// calculatePrice returns the price of token0 in token1, in whole tokens:
+// (sqrtPriceX96 / 2^96)^2 * 10^(decimals0 - decimals1).
 func calculatePrice(sqrtPriceX96 *big.Int, token0Decimals, token1Decimals int) float64 {
 	sqrtPrice := new(big.Float).SetInt(sqrtPriceX96)
 	scaleFactor := new(big.Float).SetFloat64(math.Pow(2, 96))
 	price := new(big.Float).Quo(sqrtPrice, scaleFactor)
 	priceSquared := new(big.Float).Mul(price, price)
 
-	decimalAdjustment := float64(token1Decimals - token0Decimals)
+	decimalAdjustment := float64(token0Decimals - token1Decimals)
 	decimalFactor := math.Pow(10, decimalAdjustment)
 	priceSquared.Mul(priceSquared, big.NewFloat(decimalFactor))
 
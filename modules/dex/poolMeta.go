@@ -0,0 +1,154 @@
+package dex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lisk/ethClient"
+	"lisk/logger"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PoolMeta is the data of a V3 pool that never changes. It is read on chain
+// once and kept in the pools file.
+type PoolMeta struct {
+	Token0    common.Address `json:"token0"`
+	Token1    common.Address `json:"token1"`
+	Fee       uint64         `json:"fee"`
+	Decimals0 int            `json:"decimals0"`
+	Decimals1 int            `json:"decimals1"`
+}
+
+type poolMetaCache struct {
+	mu    sync.Mutex
+	path  string
+	pools map[common.Address]PoolMeta
+}
+
+// loadPoolMetaCache reads the pools file. A missing or broken file only
+// means that the pools are read on chain again.
+func loadPoolMetaCache(path string) *poolMetaCache {
+	cache := &poolMetaCache{path: path, pools: make(map[common.Address]PoolMeta)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cache
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &cache.pools)
+	}
+	if err != nil {
+		logger.GlobalLogger.Warnf("Pools file %s is ignored: %v", path, err)
+		cache.pools = make(map[common.Address]PoolMeta)
+	}
+
+	return cache
+}
+
+// save writes the pools file atomically. Callers must hold c.mu.
+func (c *poolMetaCache) save() error {
+	data, err := json.MarshalIndent(c.pools, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to encode pools file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create pools file directory: %w", err)
+	}
+
+	tempPath := c.path + ".tmp"
+	if err := os.WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write pools file: %w", err)
+	}
+	if err := os.Rename(tempPath, c.path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write pools file: %w", err)
+	}
+	return nil
+}
+
+// poolMeta returns the metadata of pool from the cache or reads token0,
+// token1, fee and the decimals of both tokens on chain. The cache is not locked
+// during the read, so a slow node does not hold up lookups of other pools.
+func (d *Dex) poolMeta(ctx context.Context, pool common.Address) (PoolMeta, error) {
+	d.pools.mu.Lock()
+	meta, exists := d.pools.pools[pool]
+	d.pools.mu.Unlock()
+	if exists {
+		return meta, nil
+	}
+
+	meta, err := d.readPoolMeta(ctx, pool)
+	if err != nil {
+		return PoolMeta{}, err
+	}
+
+	d.pools.mu.Lock()
+	defer d.pools.mu.Unlock()
+
+	if cached, exists := d.pools.pools[pool]; exists {
+		return cached, nil
+	}
+	d.pools.pools[pool] = meta
+	if err := d.pools.save(); err != nil {
+		logger.GlobalLogger.Warnf("Failed to save pool metadata: %v", err)
+	}
+
+	return meta, nil
+}
+
+// readPoolMeta reads the metadata of pool on chain.
+func (d *Dex) readPoolMeta(ctx context.Context, pool common.Address) (PoolMeta, error) {
+	methods := []string{"token0", "token1", "fee"}
+	calls := make([]ethClient.Call, 0, len(methods))
+	for _, method := range methods {
+		data, err := d.ABI.Pack(method)
+		if err != nil {
+			return PoolMeta{}, fmt.Errorf("failed to pack %s data: %w", method, err)
+		}
+		calls = append(calls, ethClient.Call{Target: pool, CallData: data})
+	}
+
+	results, err := d.Client.Multicall(ctx, calls)
+	if err != nil {
+		return PoolMeta{}, fmt.Errorf("failed to read pool %s: %w", pool.Hex(), err)
+	}
+
+	values := make([]interface{}, 0, len(methods))
+	for i, method := range methods {
+		unpacked, err := d.ABI.Methods[method].Outputs.Unpack(results[i].ReturnData)
+		if err != nil {
+			return PoolMeta{}, fmt.Errorf("failed to unpack %s of pool %s: %w", method, pool.Hex(), err)
+		}
+		if len(unpacked) == 0 {
+			return PoolMeta{}, fmt.Errorf("empty %s of pool %s", method, pool.Hex())
+		}
+		values = append(values, unpacked[0])
+	}
+
+	token0, ok0 := values[0].(common.Address)
+	token1, ok1 := values[1].(common.Address)
+	fee, ok2 := values[2].(*big.Int)
+	if !ok0 || !ok1 || !ok2 {
+		return PoolMeta{}, fmt.Errorf("invalid metadata types of pool %s", pool.Hex())
+	}
+
+	decimals, err := d.Client.BatchDecimals(ctx, []common.Address{token0, token1})
+	if err != nil {
+		return PoolMeta{}, fmt.Errorf("failed to read decimals of pool %s: %w", pool.Hex(), err)
+	}
+
+	return PoolMeta{
+		Token0:    token0,
+		Token1:    token1,
+		Fee:       fee.Uint64(),
+		Decimals0: decimals[token0],
+		Decimals1: decimals[token1],
+	}, nil
+}
@@ -0,0 +1,81 @@
+package dex
+
+import (
+	"context"
+	"fmt"
+	"lisk/globals"
+	"math/big"
+)
+
+// PriceImpactError is returned when the quote of a swap is worse than the
+// output at the pool mid-prices by more than globals.MaxPriceImpactBps.
+type PriceImpactError struct {
+	Route     string
+	ImpactBps int64
+	MaxBps    int
+}
+
+func (e *PriceImpactError) Error() string {
+	return fmt.Sprintf("price impact of %s is %.2f%%, max %.2f%%", e.Route, float64(e.ImpactBps)/100, float64(e.MaxBps)/100)
+}
+
+func (e *PriceImpactError) Unwrap() error {
+	return globals.ErrPriceImpact
+}
+
+// checkPriceImpact compares the quote with the output amountIn would get at
+// the slot0 mid-price of every pool of the route and returns the impact in
+// basis points. The mid-price output is reduced by the pool fees, so fees are
+// not counted as price impact. The slot0 prices read with the quote are used;
+// only pools without one are read again.
+func (d *Dex) checkPriceImpact(ctx context.Context, quote *routeQuote, amountIn *big.Int) (int64, error) {
+	route := quote.Route
+	midOut := new(big.Float).SetInt(amountIn)
+
+	for i, pool := range route.Pools {
+		var sqrtPriceX96 *big.Int
+		if i < len(quote.SqrtPrices) {
+			sqrtPriceX96 = quote.SqrtPrices[i]
+		}
+		if sqrtPriceX96 == nil {
+			var err error
+			if sqrtPriceX96, err = d.sqrtPriceX96(ctx, pool); err != nil {
+				return 0, err
+			}
+		}
+
+		// price of token0 in token1, raw units: (sqrtPriceX96 / 2^96)^2
+		price := new(big.Float).Quo(new(big.Float).SetInt(sqrtPriceX96), new(big.Float).SetMantExp(big.NewFloat(1), 96))
+		price.Mul(price, price)
+		if price.Sign() == 0 {
+			return 0, fmt.Errorf("%w: pool %s has no price", globals.ErrPoolNotFound, pool.Hex())
+		}
+
+		meta, err := d.poolMeta(ctx, pool)
+		if err != nil {
+			return 0, err
+		}
+		if meta.zeroForOne(route.Tokens[i]) {
+			midOut.Mul(midOut, price)
+		} else {
+			midOut.Quo(midOut, price)
+		}
+
+		fee := new(big.Float).SetInt64(1_000_000 - route.Fees[i].Int64())
+		midOut.Mul(midOut, fee).Quo(midOut, big.NewFloat(1_000_000))
+	}
+
+	if midOut.Sign() <= 0 {
+		return 0, nil
+	}
+
+	// impact = 1 - quote / midOut, negative when the quote is better than the mid-price
+	ratio := new(big.Float).Quo(new(big.Float).SetInt(quote.AmountOut), midOut)
+	impact, _ := new(big.Float).Mul(new(big.Float).Sub(big.NewFloat(1), ratio), big.NewFloat(10000)).Float64()
+	impactBps := int64(impact)
+
+	if impactBps > int64(globals.MaxPriceImpactBps) {
+		return impactBps, &PriceImpactError{Route: route.String(), ImpactBps: impactBps, MaxBps: globals.MaxPriceImpactBps}
+	}
+	return impactBps, nil
+}
@@ -0,0 +1,258 @@
+package dex
+
+import (
+	"context"
+	"fmt"
+	"lisk/ethClient"
+	"lisk/globals"
+	"lisk/logger"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	maxParallelQuotes = 8
+	// gasReferenceAmount of WETH is quoted to convert gas costs into the
+	// output token, 0.001 ETH.
+	gasReferenceAmount = 1e15
+)
+
+// routeQuote is the answer of the Quoter for one route.
+type routeQuote struct {
+	Route       swapRoute
+	Path        []byte
+	AmountOut   *big.Int
+	GasEstimate uint64
+	GasCost     *big.Int // GasEstimate at the current gas price, in the output token
+	Net         *big.Int // AmountOut - GasCost
+	// SqrtPrices are the slot0 prices of the pools of the route, read
+	// together with the quotes; nil where the read failed.
+	SqrtPrices []*big.Int
+}
+
+// bestRoute quotes every route of the pool graph from tokenIn to tokenOut in
+// parallel and returns the one with the largest output net of its gas cost,
+// together with the number of routes that could be quoted.
+func (d *Dex) bestRoute(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int) (*routeQuote, int, error) {
+	graph, err := d.poolGraph(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	routes := graph.routes(tokenIn, tokenOut)
+	if len(routes) == 0 {
+		return nil, 0, fmt.Errorf("%w: no pools between %s and %s", globals.ErrPoolNotFound, tokenIn.Hex(), tokenOut.Hex())
+	}
+
+	var (
+		g                errgroup.Group
+		quotes           []*routeQuote
+		gasNum, gasDenom *big.Int
+		sqrtPrices       map[common.Address]*big.Int
+	)
+	g.Go(func() error {
+		quotes, err = d.quoteRoutes(ctx, routes, amountIn)
+		return err
+	})
+	g.Go(func() error {
+		sqrtPrices = d.slot0Prices(ctx, routes)
+		return nil
+	})
+	g.Go(func() error {
+		gasNum, gasDenom = d.gasPriceIn(ctx, graph, tokenOut)
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		best   *routeQuote
+		quoted int
+	)
+	for _, quote := range quotes {
+		if quote == nil || quote.AmountOut.Sign() == 0 {
+			continue
+		}
+		quoted++
+
+		quote.GasCost = new(big.Int).SetUint64(quote.GasEstimate)
+		quote.GasCost.Mul(quote.GasCost, gasNum).Quo(quote.GasCost, gasDenom)
+		quote.Net = new(big.Int).Sub(quote.AmountOut, quote.GasCost)
+
+		if best == nil || quote.Net.Cmp(best.Net) > 0 {
+			best = quote
+		}
+	}
+
+	if best == nil {
+		return nil, 0, fmt.Errorf("%w: no route can swap %s -> %s", globals.ErrPoolNotFound, tokenIn.Hex(), tokenOut.Hex())
+	}
+
+	best.SqrtPrices = make([]*big.Int, len(best.Route.Pools))
+	for i, pool := range best.Route.Pools {
+		best.SqrtPrices[i] = sqrtPrices[pool]
+	}
+	return best, quoted, nil
+}
+
+// slot0Prices reads the slot0 price of every pool of the routes in one
+// Multicall. Pools that cannot be read are missing from the result.
+func (d *Dex) slot0Prices(ctx context.Context, routes []swapRoute) map[common.Address]*big.Int {
+	prices := make(map[common.Address]*big.Int)
+
+	data, err := d.ABI.Pack("slot0")
+	if err != nil {
+		return prices
+	}
+
+	var (
+		pools []common.Address
+		calls []ethClient.Call
+		seen  = make(map[common.Address]bool)
+	)
+	for _, route := range routes {
+		for _, pool := range route.Pools {
+			if seen[pool] {
+				continue
+			}
+			seen[pool] = true
+			pools = append(pools, pool)
+			calls = append(calls, ethClient.Call{Target: pool, AllowFailure: true, CallData: data})
+		}
+	}
+
+	results, err := d.Client.Multicall(ctx, calls)
+	if err != nil {
+		logger.GlobalLogger.Warnf("[Oku] Failed to read pool prices: %v", err)
+		return prices
+	}
+
+	for i, result := range results {
+		if !result.Success {
+			continue
+		}
+		unpacked, err := d.ABI.Methods["slot0"].Outputs.Unpack(result.ReturnData)
+		if err != nil || len(unpacked) == 0 {
+			continue
+		}
+		if sqrtPriceX96, ok := unpacked[0].(*big.Int); ok {
+			prices[pools[i]] = sqrtPriceX96
+		}
+	}
+	return prices
+}
+
+// quoteRoutes quotes the routes with at most maxParallelQuotes calls at a
+// time. The quote of a route without a usable pool is nil.
+func (d *Dex) quoteRoutes(ctx context.Context, routes []swapRoute, amountIn *big.Int) ([]*routeQuote, error) {
+	quotes := make([]*routeQuote, len(routes))
+
+	var g errgroup.Group
+	g.SetLimit(maxParallelQuotes)
+
+	for i, route := range routes {
+		g.Go(func() error {
+			path, err := d.encodeV3Path(route)
+			if err != nil {
+				return err
+			}
+
+			amountOut, gasEstimate, err := d.quoteExactInput(ctx, path, amountIn)
+			if err != nil {
+				if verifyError(err) {
+					return nil
+				}
+				return fmt.Errorf("failed to quote %s: %w", route, err)
+			}
+
+			quotes[i] = &routeQuote{Route: route, Path: path, AmountOut: amountOut, GasEstimate: gasEstimate}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return quotes, nil
+}
+
+// gasPriceIn returns the current price of one gas in units of token as the
+// fraction num/denom. When the price cannot be found, routes are ranked by
+// their output alone (num = 0).
+func (d *Dex) gasPriceIn(ctx context.Context, graph poolGraph, token common.Address) (*big.Int, *big.Int) {
+	gasPrice, err := d.Client.GasPrice(ctx)
+	if err != nil {
+		logger.GlobalLogger.Warnf("[Oku] Routes are ranked without gas costs: %v", err)
+		return big.NewInt(0), big.NewInt(1)
+	}
+
+	if token == globals.WETH {
+		return gasPrice, big.NewInt(1)
+	}
+
+	reference := big.NewInt(gasReferenceAmount)
+	quotes, err := d.quoteRoutes(ctx, graph.routes(globals.WETH, token), reference)
+	if err != nil {
+		logger.GlobalLogger.Warnf("[Oku] Routes are ranked without gas costs: %v", err)
+		return big.NewInt(0), big.NewInt(1)
+	}
+
+	var referenceOut *big.Int
+	for _, quote := range quotes {
+		if quote != nil && (referenceOut == nil || quote.AmountOut.Cmp(referenceOut) > 0) {
+			referenceOut = quote.AmountOut
+		}
+	}
+	if referenceOut == nil {
+		logger.GlobalLogger.Warnf("[Oku] Routes are ranked without gas costs: no WETH route to %s", tokenSymbol(token))
+		return big.NewInt(0), big.NewInt(1)
+	}
+
+	return new(big.Int).Mul(gasPrice, referenceOut), reference
+}
+
+// quoteExactInput returns the amount of the last token of the path that the
+// QuoterV2 expects for amountIn and the gas the swap is estimated to use.
+func (d *Dex) quoteExactInput(ctx context.Context, path []byte, amountIn *big.Int) (*big.Int, uint64, error) {
+	data, err := d.ABI.Pack("quoteExactInput", path, amountIn)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to pack ABI data: %w", err)
+	}
+
+	response, err := d.Client.CallCA(ctx, d.Quoter, data)
+	if err != nil {
+		return nil, 0, fmt.Errorf("call to Quoter failed: %w", err)
+	}
+	if len(response) == 0 {
+		return nil, 0, fmt.Errorf("empty response from contract call")
+	}
+
+	unpackedData, err := d.ABI.Unpack("quoteExactInput", response)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to unpack ABI data: %w", err)
+	}
+
+	amountOut, ok := unpackedData[0].(*big.Int)
+	if !ok {
+		return nil, 0, fmt.Errorf("error of conversion to *big.Int")
+	}
+	gasEstimate, ok := unpackedData[3].(*big.Int)
+	if !ok {
+		return nil, 0, fmt.Errorf("error of conversion to *big.Int")
+	}
+
+	return amountOut, gasEstimate.Uint64(), nil
+}
+
+// feeTiers lists the fee tiers of the route, e.g. "500,3000".
+func (r swapRoute) feeTiers() string {
+	tiers := make([]string, 0, len(r.Fees))
+	for _, fee := range r.Fees {
+		tiers = append(tiers, fee.String())
+	}
+	return strings.Join(tiers, ",")
+}
@@ -1,6 +1,7 @@
 package eligbleChecker
 
 import (
+	"context"
 	"lisk/account"
 	"lisk/globals"
 	"lisk/httpClient"
@@ -21,13 +22,13 @@ func NewChecker(hc *httpClient.HttpClient) (*Checker, error) {
 	}, nil
 }
 
-func (c *Checker) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, operation globals.ActionType) error {
+func (c *Checker) Action(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, operation globals.ActionType) error {
 	requstPayload := map[string]string{
 		"wallet_address": acc.Address.String(),
 	}
 
 	resp := &models.CheckResponse{} // Создаем объект перед использованием
-	if err := c.HttpClinet.SendJSONRequest("https://lisk.com/wp-json/bornfight/v1/eligibility-check", "POST", requstPayload, resp); err != nil {
+	if err := c.HttpClinet.SendJSONRequest(ctx, "https://lisk.com/wp-json/bornfight/v1/eligibility-check", "POST", requstPayload, resp); err != nil {
 		return err
 	}
 
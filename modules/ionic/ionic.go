@@ -1,6 +1,7 @@
 package ionic
 
 import (
+	"context"
 	"fmt"
 	"lisk/account"
 	"lisk/ethClient"
@@ -36,7 +37,7 @@ func NewIonic(addresses map[string]string, abi *abi.ABI, client *ethClient.Clien
 	}, nil
 }
 
-func (i *Ionic) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, operation globals.ActionType) error {
+func (i *Ionic) Action(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, operation globals.ActionType) error {
 	data, err := i.prepareTx(operation, amountIn, tokenIn)
 	if err != nil {
 		return err
@@ -44,17 +45,17 @@ func (i *Ionic) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc
 
 	switch operation {
 	case globals.Supply, globals.Repay:
-		if err := i.ensureAllowance(tokenIn, acc, amountIn); err != nil {
+		if err := i.ensureAllowance(ctx, tokenIn, acc, amountIn); err != nil {
 			return fmt.Errorf("failed to approve tokens: %w", err)
 		}
 	}
 	addressCA := i.prepareCA(tokenIn, operation)
 
-	return i.Client.SendTransaction(acc.PrivateKey, acc.Address, addressCA, i.Client.GetNonce(acc.Address), big.NewInt(0), data)
+	return i.Client.SendTransaction(ctx, acc.Signer, addressCA, big.NewInt(0), data)
 }
 
-func (i *Ionic) ensureAllowance(tokenIn common.Address, acc *account.Account, amountIn *big.Int) error {
-	if _, err := i.Client.ApproveTx(tokenIn, i.Tokens[tokenIn], acc, globals.MaxUint256, false); err != nil {
+func (i *Ionic) ensureAllowance(ctx context.Context, tokenIn common.Address, acc *account.Account, amountIn *big.Int) error {
+	if _, err := i.Client.ApproveTx(ctx, tokenIn, i.Tokens[tokenIn], acc, globals.MaxUint256, false); err != nil {
 		return err
 	}
 
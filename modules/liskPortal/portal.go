@@ -1,6 +1,7 @@
 package liskPortal
 
 import (
+	"context"
 	"fmt"
 	"lisk/account"
 	"lisk/globals"
@@ -30,7 +31,7 @@ func NewPortal(taskEndpoint, checkerEndpoint string) (*Portal, error) {
 	}, nil
 }
 
-func (p *Portal) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error {
+func (p *Portal) Action(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error {
 	client, err := httpClient.NewHttpClient(acc.Proxy)
 	if err != nil {
 		return err
@@ -38,17 +39,17 @@ func (p *Portal) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc
 
 	switch ta {
 	case globals.DailyCheck:
-		return p.handleSingleTask(client, acc, ta, p.TaskEndpoint)
+		return p.handleSingleTask(ctx, client, acc, ta, p.TaskEndpoint)
 
 	case globals.MainTasks:
-		return p.handleMultipleTasks(client, acc)
+		return p.handleMultipleTasks(ctx, client, acc)
 
 	default:
-		return p.handleSingleTask(client, acc, ta, p.CheckerEndpoint)
+		return p.handleSingleTask(ctx, client, acc, ta, p.CheckerEndpoint)
 	}
 }
 
-func (p *Portal) handleSingleTask(client *httpClient.HttpClient, acc *account.Account, ta globals.ActionType, endpoint string) error {
+func (p *Portal) handleSingleTask(ctx context.Context, client *httpClient.HttpClient, acc *account.Account, ta globals.ActionType, endpoint string) error {
 	taskID, exists := globals.LiskPortalIDs[ta][ta]
 	if !exists {
 		return fmt.Errorf("task ID not found for action type: %s", ta)
@@ -60,14 +61,14 @@ func (p *Portal) handleSingleTask(client *httpClient.HttpClient, acc *account.Ac
 	}
 
 	var result models.TaskResponse
-	if err := client.SendJSONRequest(endpoint, "POST", request, &result); err != nil {
+	if err := client.SendJSONRequest(ctx, endpoint, "POST", request, &result); err != nil {
 		return fmt.Errorf("failed to execute task %d: %w", taskID, err)
 	}
 
 	return p.processTaskResult(taskID, result, ta, acc.Address)
 }
 
-func (p *Portal) handleMultipleTasks(client *httpClient.HttpClient, acc *account.Account) error {
+func (p *Portal) handleMultipleTasks(ctx context.Context, client *httpClient.HttpClient, acc *account.Account) error {
 	taskMap, exists := globals.LiskPortalIDs[globals.MainTasks]
 	if !exists {
 		return fmt.Errorf("no tasks found for MainTasks")
@@ -81,13 +82,15 @@ func (p *Portal) handleMultipleTasks(client *httpClient.HttpClient, acc *account
 		}
 
 		var result models.TaskResponse
-		if err := client.SendJSONRequest(p.TaskEndpoint, "POST", request, &result); err != nil {
+		if err := client.SendJSONRequest(ctx, p.TaskEndpoint, "POST", request, &result); err != nil {
 			logger.GlobalLogger.Errorf("Failed to execute task %d: %v", taskID, err)
 			continue
 		}
 
 		p.processTaskResult(taskID, result, subTaskType, acc.Address)
-		time.Sleep(time.Second * 1)
+		if err := utils.SleepContext(ctx, time.Second*1); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package relay
 
 import (
+	"context"
 	"encoding/hex"
 	"fmt"
 	"lisk/account"
@@ -23,7 +24,7 @@ func NewRelay(clients map[globals.ActionType]*ethClient.Client, endpoint string)
 	return &Relay{Client: clients, Endpoint: endpoint}, nil
 }
 
-func (r *Relay) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error {
+func (r *Relay) Action(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error {
 	if ta == "" {
 		return fmt.Errorf("A null client ethereum value was transmitted. Check RPC: %v", ta)
 	}
@@ -33,12 +34,12 @@ func (r *Relay) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc
 		return err
 	}
 
-	chainID, err := r.Client[ta].GetChainID()
+	chainID, err := r.Client[ta].GetChainID(ctx)
 	if err != nil {
 		return err
 	}
 
-	quoteData, err := r.getQuoteData(tokenIn, tokenOut, amountIn, int(chainID), acc, client)
+	quoteData, err := r.getQuoteData(ctx, tokenIn, tokenOut, amountIn, int(chainID), acc, client)
 	if err != nil {
 		return err
 	}
@@ -48,10 +49,10 @@ func (r *Relay) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc
 		return err
 	}
 
-	return r.Client[ta].SendTransaction(acc.PrivateKey, acc.Address, to, r.Client[ta].GetNonce(acc.Address), value, data)
+	return r.Client[ta].SendTransaction(ctx, acc.Signer, to, value, data)
 }
 
-func (r *Relay) getQuoteData(tokenIn, tokenOut common.Address, amountIn *big.Int, chainID int, acc *account.Account, client *httpClient.HttpClient) (*models.RelayResponse, error) {
+func (r *Relay) getQuoteData(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, chainID int, acc *account.Account, client *httpClient.HttpClient) (*models.RelayResponse, error) {
 	request := models.RelayRequest{
 		User:                 acc.Address.Hex(),
 		OriginChainId:        chainID,
@@ -67,7 +68,7 @@ func (r *Relay) getQuoteData(tokenIn, tokenOut common.Address, amountIn *big.Int
 	}
 
 	var result models.RelayResponse
-	if err := client.SendJSONRequest(r.Endpoint, "POST", request, &result); err != nil {
+	if err := client.SendJSONRequest(ctx, r.Endpoint, "POST", request, &result); err != nil {
 		return nil, err
 	}
 
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"fmt"
 	"lisk/account"
 	"lisk/ethClient"
@@ -38,7 +39,7 @@ func NewTestIonic(addresses map[string]string, abi *abi.ABI, client *ethClient.C
 	}, nil
 }
 
-func (i *IonicTest) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error {
+func (i *IonicTest) Action(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error {
 	logger.GlobalLogger.Infof("это входная функция ионика. Вызов...")
 	_, _ = i.prepareTx(ta, amountIn, tokenIn)
 	return nil
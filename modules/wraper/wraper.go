@@ -1,6 +1,7 @@
 package wraper
 
 import (
+	"context"
 	"fmt"
 	"lisk/account"
 	"lisk/ethClient"
@@ -20,7 +21,7 @@ func NewWraper(client *ethClient.Client) (*Wraper, error) {
 	}, nil
 }
 
-func (w *Wraper) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error {
+func (w *Wraper) Action(ctx context.Context, tokenIn, tokenOut common.Address, amountIn *big.Int, acc *account.Account, ta globals.ActionType) error {
 	data, err := w.packData(ta, amountIn)
 	if err != nil {
 		return nil
@@ -31,7 +32,7 @@ func (w *Wraper) Action(tokenIn, tokenOut common.Address, amountIn *big.Int, acc
 		value = big.NewInt(0)
 	}
 
-	return w.Client.SendTransaction(acc.PrivateKey, acc.Address, globals.WETH, w.Client.GetNonce(acc.Address), value, data)
+	return w.Client.SendTransaction(ctx, acc.Signer, globals.WETH, value, data)
 }
 
 func (w *Wraper) packData(typePack globals.ActionType, amountIn *big.Int) ([]byte, error) {
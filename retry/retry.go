@@ -0,0 +1,228 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lisk/globals"
+	"lisk/logger"
+	"math"
+	"math/rand"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Error classes a policy can filter on.
+const (
+	ClassInsufficientBalance = "insufficient_balance"
+	ClassGasTimeout          = "gas_timeout"
+	ClassPoolNotFound        = "pool_not_found"
+	ClassPriceImpact         = "price_impact"
+	ClassRateLimited         = "rate_limited"
+	ClassNonce               = "nonce"
+	ClassReverted            = "reverted"
+	ClassNetwork             = "network"
+	ClassOther               = "other"
+	ClassAny                 = "any"
+)
+
+var classes = []string{
+	ClassInsufficientBalance, ClassGasTimeout, ClassPoolNotFound, ClassPriceImpact, ClassRateLimited,
+	ClassNonce, ClassReverted, ClassNetwork, ClassOther, ClassAny,
+}
+
+// Policy describes how often and when an operation is repeated: up to
+// MaxAttempts runs in total, with exponential backoff and jitter between them,
+// and only for errors whose class is listed in RetryOn.
+type Policy struct {
+	MaxAttempts int      `json:"max_attempts"`
+	BaseDelayMs int      `json:"base_delay_ms"`
+	MaxDelayMs  int      `json:"max_delay_ms"`
+	Multiplier  float64  `json:"multiplier"`
+	Jitter      float64  `json:"jitter"` // 0.2 => every delay is randomised by ±20%
+	RetryOn     []string `json:"retry_on"`
+}
+
+var (
+	// Action is used for module actions in performActions.
+	Action = Policy{
+		MaxAttempts: 4,
+		BaseDelayMs: 5000,
+		MaxDelayMs:  60000,
+		Multiplier:  2,
+		Jitter:      0.2,
+		RetryOn:     []string{ClassRateLimited, ClassNonce, ClassReverted, ClassNetwork, ClassOther},
+	}
+
+	// RPC is used for idempotent calls of the RPC pool.
+	RPC = Policy{
+		MaxAttempts: 3,
+		BaseDelayMs: 500,
+		MaxDelayMs:  5000,
+		Multiplier:  2,
+		Jitter:      0.2,
+		RetryOn:     []string{ClassNetwork, ClassRateLimited},
+	}
+
+	// HTTP is used for requests of httpClient (relay, portal, checker).
+	HTTP = Policy{
+		MaxAttempts: 3,
+		BaseDelayMs: 1500,
+		MaxDelayMs:  15000,
+		Multiplier:  2,
+		Jitter:      0.2,
+		RetryOn:     []string{ClassNetwork, ClassRateLimited},
+	}
+)
+
+// SetPolicies replaces the default policies with the ones from config. Fields
+// that are not set keep their default values. Every policy is validated
+// first; with an invalid one none of them is applied.
+func SetPolicies(policies map[string]Policy) error {
+	targets := map[string]*Policy{"action": &Action, "rpc": &RPC, "http": &HTTP}
+	merged := make(map[*Policy]Policy, len(policies))
+
+	for name, policy := range policies {
+		target, exists := targets[name]
+		if !exists {
+			return fmt.Errorf("unknown retry policy %q, expected action, rpc or http", name)
+		}
+
+		for _, class := range policy.RetryOn {
+			if !slices.Contains(classes, class) {
+				return fmt.Errorf("retry policy %s: unknown error class %q", name, class)
+			}
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			return fmt.Errorf("retry policy %s: jitter must be between 0 and 1", name)
+		}
+
+		result := *target
+		if policy.MaxAttempts > 0 {
+			result.MaxAttempts = policy.MaxAttempts
+		}
+		if policy.BaseDelayMs > 0 {
+			result.BaseDelayMs = policy.BaseDelayMs
+		}
+		if policy.MaxDelayMs > 0 {
+			result.MaxDelayMs = policy.MaxDelayMs
+		}
+		if policy.Multiplier >= 1 {
+			result.Multiplier = policy.Multiplier
+		}
+		if policy.Jitter > 0 {
+			result.Jitter = policy.Jitter
+		}
+		if policy.RetryOn != nil {
+			result.RetryOn = policy.RetryOn
+		}
+		merged[target] = result
+	}
+
+	for target, policy := range merged {
+		*target = policy
+	}
+	return nil
+}
+
+// Classify returns the error class of err.
+func Classify(err error) string {
+	switch {
+	case errors.Is(err, globals.ErrInsufficientBalance):
+		return ClassInsufficientBalance
+	case errors.Is(err, globals.ErrGasTimeout):
+		return ClassGasTimeout
+	case errors.Is(err, globals.ErrPoolNotFound):
+		return ClassPoolNotFound
+	case errors.Is(err, globals.ErrPriceImpact):
+		return ClassPriceImpact
+	case errors.Is(err, globals.ErrRPCRateLimited):
+		return ClassRateLimited
+	case errors.Is(err, globals.ErrNonceTooLow):
+		return ClassNonce
+	case errors.Is(err, globals.ErrReverted):
+		return ClassReverted
+	}
+
+	if status, ok := httpStatus(err); ok {
+		switch {
+		case status == 429:
+			return ClassRateLimited
+		case status >= 500:
+			return ClassNetwork
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) || errors.Is(err, context.DeadlineExceeded) {
+		return ClassNetwork
+	}
+
+	return ClassOther
+}
+
+// httpStatus extracts the status code of HTTP errors of the RPC client and of
+// httpClient, which implements HTTPStatus().
+func httpStatus(err error) (int, bool) {
+	var rpcErr rpc.HTTPError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.StatusCode, true
+	}
+
+	var statusErr interface{ HTTPStatus() int }
+	if errors.As(err, &statusErr) {
+		return statusErr.HTTPStatus(), true
+	}
+
+	return 0, false
+}
+
+// Matches reports whether the class of err is listed in RetryOn.
+func (p Policy) Matches(err error) bool {
+	class := Classify(err)
+	return slices.Contains(p.RetryOn, ClassAny) || slices.Contains(p.RetryOn, class)
+}
+
+// ShouldRetry reports whether another attempt is allowed after attempt
+// (1-based) failed with err.
+func (p Policy) ShouldRetry(err error, attempt int) bool {
+	return err != nil && attempt < p.MaxAttempts && p.Matches(err)
+}
+
+// Delay returns the pause before retry number retry (1-based).
+func (p Policy) Delay(retry int) time.Duration {
+	delay := float64(p.BaseDelayMs) * math.Pow(p.Multiplier, float64(max(retry-1, 0)))
+	if p.MaxDelayMs > 0 && delay > float64(p.MaxDelayMs) {
+		delay = float64(p.MaxDelayMs)
+	}
+	if p.Jitter > 0 {
+		delay *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(delay) * time.Millisecond
+}
+
+// Do runs fn until it succeeds or the policy stops it and returns the number
+// of retries that were made.
+func (p Policy) Do(ctx context.Context, name string, fn func() error) (int, error) {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || ctx.Err() != nil || !p.ShouldRetry(err, attempt) {
+			return attempt - 1, err
+		}
+
+		delay := p.Delay(attempt)
+		logger.GlobalLogger.Warnf("[RETRY] %s failed (%s): %v. Retry %d of %d in %v", name, Classify(err), err, attempt, p.MaxAttempts-1, delay.Round(time.Millisecond))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt - 1, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
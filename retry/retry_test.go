@@ -0,0 +1,139 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lisk/globals"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type statusError int
+
+func (e statusError) Error() string   { return fmt.Sprintf("status %d", int(e)) }
+func (e statusError) HTTPStatus() int { return int(e) }
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{fmt.Errorf("swap: %w", globals.ErrInsufficientBalance), ClassInsufficientBalance},
+		{fmt.Errorf("estimate: %w", globals.ErrGasTimeout), ClassGasTimeout},
+		{fmt.Errorf("%w: USDC/LISK", globals.ErrPoolNotFound), ClassPoolNotFound},
+		{fmt.Errorf("%w: 5%%", globals.ErrPriceImpact), ClassPriceImpact},
+		{fmt.Errorf("%w on lisk", globals.ErrRPCRateLimited), ClassRateLimited},
+		{fmt.Errorf("%w: nonce too low", globals.ErrNonceTooLow), ClassNonce},
+		{fmt.Errorf("call: %w", globals.ErrReverted), ClassReverted},
+		{rpc.HTTPError{StatusCode: 429}, ClassRateLimited},
+		{rpc.HTTPError{StatusCode: 502}, ClassNetwork},
+		{rpc.HTTPError{StatusCode: 404}, ClassOther},
+		{fmt.Errorf("relay: %w", statusError(503)), ClassNetwork},
+		{fmt.Errorf("read: %w", io.ErrUnexpectedEOF), ClassNetwork},
+		{context.DeadlineExceeded, ClassNetwork},
+		{errors.New("something else"), ClassOther},
+	}
+
+	for _, tt := range tests {
+		if got := Classify(tt.err); got != tt.want {
+			t.Errorf("Classify(%v) = %s, want %s", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, RetryOn: []string{ClassNetwork}}
+	network := io.ErrUnexpectedEOF
+
+	tests := []struct {
+		err     error
+		attempt int
+		want    bool
+	}{
+		{nil, 1, false},
+		{network, 1, true},
+		{network, 2, true},
+		{network, 3, false},
+		{globals.ErrInsufficientBalance, 1, false},
+	}
+	for _, tt := range tests {
+		if got := policy.ShouldRetry(tt.err, tt.attempt); got != tt.want {
+			t.Errorf("ShouldRetry(%v, %d) = %v, want %v", tt.err, tt.attempt, got, tt.want)
+		}
+	}
+
+	any := Policy{MaxAttempts: 2, RetryOn: []string{ClassAny}}
+	if !any.ShouldRetry(errors.New("something else"), 1) {
+		t.Error("policy with any does not retry every class")
+	}
+}
+
+func TestDelay(t *testing.T) {
+	policy := Policy{BaseDelayMs: 100, MaxDelayMs: 1000, Multiplier: 2}
+
+	want := []time.Duration{100, 200, 400, 800, 1000, 1000}
+	for i, w := range want {
+		if got := policy.Delay(i + 1); got != w*time.Millisecond {
+			t.Errorf("Delay(%d) = %v, want %v", i+1, got, w*time.Millisecond)
+		}
+	}
+
+	policy.Jitter = 0.2
+	for i := 0; i < 100; i++ {
+		if got := policy.Delay(2); got < 160*time.Millisecond || got > 240*time.Millisecond {
+			t.Fatalf("Delay with 20%% jitter = %v, want 160ms..240ms", got)
+		}
+	}
+}
+
+func TestDo(t *testing.T) {
+	policy := Policy{MaxAttempts: 3, BaseDelayMs: 1, Multiplier: 1, RetryOn: []string{ClassNetwork}}
+
+	calls := 0
+	retries, err := policy.Do(context.Background(), "test", func() error {
+		calls++
+		if calls < 3 {
+			return io.ErrUnexpectedEOF
+		}
+		return nil
+	})
+	if err != nil || retries != 2 {
+		t.Fatalf("Do = %d retries, %v; want 2 retries and success", retries, err)
+	}
+
+	calls = 0
+	_, err = policy.Do(context.Background(), "test", func() error {
+		calls++
+		return globals.ErrInsufficientBalance
+	})
+	if calls != 1 || !errors.Is(err, globals.ErrInsufficientBalance) {
+		t.Fatalf("Do retried a class that is not listed: %d calls, %v", calls, err)
+	}
+}
+
+func TestSetPoliciesAllOrNothing(t *testing.T) {
+	defaults := [3]Policy{Action, RPC, HTTP}
+	t.Cleanup(func() { Action, RPC, HTTP = defaults[0], defaults[1], defaults[2] })
+
+	err := SetPolicies(map[string]Policy{
+		"action": {MaxAttempts: 9},
+		"rpc":    {RetryOn: []string{"unknown"}},
+	})
+	if err == nil {
+		t.Fatal("invalid policy was accepted")
+	}
+	if Action.MaxAttempts != defaults[0].MaxAttempts {
+		t.Fatal("valid policy was applied although another one is invalid")
+	}
+
+	if err := SetPolicies(map[string]Policy{"action": {MaxAttempts: 9}}); err != nil {
+		t.Fatal(err)
+	}
+	if Action.MaxAttempts != 9 || Action.BaseDelayMs != defaults[0].BaseDelayMs {
+		t.Fatalf("merged policy = %+v", Action)
+	}
+}
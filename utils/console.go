@@ -95,6 +95,15 @@ func ResotoreProcess() string {
 	}
 }
 
+// PromptPassphrase asks for a passphrase without echoing it.
+func PromptPassphrase(message string) (string, error) {
+	var passphrase string
+	if err := survey.AskOne(&survey.Password{Message: message}, &passphrase); err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return passphrase, nil
+}
+
 func promptSelection(message string, options []string) string {
 	var selected string
 	if err := survey.AskOne(&survey.Select{
@@ -4,6 +4,7 @@ import (
 	"encoding/csv"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
 	"sort"
 	"strconv"
@@ -13,6 +14,11 @@ import (
 	"lisk/models"
 )
 
+var statsHeader = []string{"Week", "AccountAddress", "Module", "TotalSuccess", "TodaySuccess", "TodayDate", "Retries", "GasUsed", "FeesWei", "FeesETH", "FailedTxs"}
+
+// ReadStatsFromCSV reads the stats file by column names, so files written by
+// older versions (without the newer columns) and files with extra columns
+// are both accepted. Missing numeric columns are read as 0.
 func ReadStatsFromCSV(filePath string) (map[globals.StatKey]models.StatRecord, error) {
 	statsMap := make(map[globals.StatKey]models.StatRecord)
 
@@ -26,9 +32,23 @@ func ReadStatsFromCSV(filePath string) (map[globals.StatKey]models.StatRecord, e
 	defer file.Close()
 
 	reader := csv.NewReader(file)
-	if _, err := reader.Read(); err != nil {
-		if err != io.EOF {
-			return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return statsMap, nil
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range statsHeader[:3] {
+		if _, exists := columns[required]; !exists {
+			return nil, fmt.Errorf("CSV header has no %s column", required)
 		}
 	}
 
@@ -41,36 +61,58 @@ func ReadStatsFromCSV(filePath string) (map[globals.StatKey]models.StatRecord, e
 			return nil, fmt.Errorf("error reading CSV record: %w", err)
 		}
 
-		if len(record) != 6 {
-			continue
+		field := func(name string) string {
+			i, exists := columns[name]
+			if !exists || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		intField := func(name string) (int, error) {
+			value := field(name)
+			if value == "" {
+				return 0, nil
+			}
+			return strconv.Atoi(value)
 		}
 
-		week, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		week, err := strconv.Atoi(field("Week"))
 		if err != nil {
 			continue
 		}
-		address := strings.TrimSpace(record[1])
-		module := strings.TrimSpace(record[2])
+		address := field("AccountAddress")
+		module := field("Module")
 
-		totalSuccess, err := strconv.Atoi(strings.TrimSpace(record[3]))
-		if err != nil {
+		var stat models.StatRecord
+		if stat.TotalSuccess, err = intField("TotalSuccess"); err != nil {
 			continue
 		}
-		todaySuccess, err := strconv.Atoi(strings.TrimSpace(record[4]))
-		if err != nil {
+		if stat.TodaySuccess, err = intField("TodaySuccess"); err != nil {
 			continue
 		}
-		todayDate, err := strconv.Atoi(strings.TrimSpace(record[5]))
-		if err != nil {
+		if stat.TodayDate, err = intField("TodayDate"); err != nil {
 			continue
 		}
-
-		key := BuildStatKey(week, address, module)
-		statsMap[key] = models.StatRecord{
-			TotalSuccess: totalSuccess,
-			TodaySuccess: todaySuccess,
-			TodayDate:    todayDate,
+		if stat.Retries, err = intField("Retries"); err != nil {
+			continue
+		}
+		if stat.FailedTxs, err = intField("FailedTxs"); err != nil {
+			continue
 		}
+		if value := field("GasUsed"); value != "" {
+			if stat.GasUsed, err = strconv.ParseUint(value, 10, 64); err != nil {
+				continue
+			}
+		}
+		// FeesETH is written for reading only, the exact value is in FeesWei.
+		stat.FeesWei = big.NewInt(0)
+		if value := field("FeesWei"); value != "" {
+			if _, ok := stat.FeesWei.SetString(value, 10); !ok {
+				continue
+			}
+		}
+
+		statsMap[BuildStatKey(week, address, module)] = stat
 	}
 
 	return statsMap, nil
@@ -90,8 +132,7 @@ func WriteStatsToCSV(filePath string, statsMap map[globals.StatKey]models.StatRe
 	writer := csv.NewWriter(file)
 	defer writer.Flush()
 
-	header := []string{"Week", "AccountAddress", "Module", "TotalSuccess", "TodaySuccess", "TodayDate"}
-	if err := writer.Write(header); err != nil {
+	if err := writer.Write(statsHeader); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
 
@@ -121,6 +162,11 @@ func WriteStatsToCSV(filePath string, statsMap map[globals.StatKey]models.StatRe
 			continue
 		}
 
+		fees := stats.FeesWei
+		if fees == nil {
+			fees = big.NewInt(0)
+		}
+
 		record := []string{
 			strconv.Itoa(week),
 			address,
@@ -128,6 +174,11 @@ func WriteStatsToCSV(filePath string, statsMap map[globals.StatKey]models.StatRe
 			strconv.Itoa(stats.TotalSuccess),
 			strconv.Itoa(stats.TodaySuccess),
 			strconv.Itoa(stats.TodayDate),
+			strconv.Itoa(stats.Retries),
+			strconv.FormatUint(stats.GasUsed, 10),
+			fees.String(),
+			ConvertFromWei(fees, 18),
+			strconv.Itoa(stats.FailedTxs),
 		}
 
 		if err := writer.Write(record); err != nil {
@@ -187,9 +238,21 @@ func ReplacePrivateKey(pk, addr string) error {
 func WriteAddrTokenBalancesToFile(balances map[string]map[string]string) error {
 	lines := make([]string, 0)
 
-	for addr, tokens := range balances {
-		for token, balance := range tokens {
-			line := FormatAddrTokenBalance(addr, token, balance)
+	addrs := make([]string, 0, len(balances))
+	for addr := range balances {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	for _, addr := range addrs {
+		tokens := make([]string, 0, len(balances[addr]))
+		for token := range balances[addr] {
+			tokens = append(tokens, token)
+		}
+		sort.Strings(tokens)
+
+		for _, token := range tokens {
+			line := FormatAddrTokenBalance(addr, token, balances[addr][token])
 			lines = append(lines, line)
 		}
 	}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"lisk/globals"
 	"lisk/httpClient"
 	"lisk/logger"
@@ -23,7 +24,7 @@ func GasPricesPrint() {
 	}
 
 	var result models.BlockscoutResp
-	if err := client.SendJSONRequest(globals.Blockscout, "GET", nil, &result); err != nil {
+	if err := client.SendJSONRequest(context.Background(), globals.Blockscout, "GET", nil, &result); err != nil {
 		return
 	}
 	logger.GlobalLogger.Infof("==========================================================")
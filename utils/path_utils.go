@@ -2,14 +2,22 @@ package utils
 
 func GetPath(path string) string {
 	paths := map[string]string{
-		"privateKeys":  "account/privateKeys.txt",
-		"config":       "config/config.json",
-		"proxy":        "account/proxy.txt",
-		"stats":        "account/account_stats.csv",
-		"error":        "account/error_accs.csv",
-		"balances":     "account/balances_accs.csv",
-		"task_results": "account/points.csv",
-		"eligble":      "account/eligble.csv",
+		"privateKeys":    "account/privateKeys.txt",
+		"config":         "config/config.json",
+		"scenario":       "config/scenario.json",
+		"schedule":       "config/schedule.json",
+		"schedule_state": "account/schedule_state.json",
+		"proxy":          "account/proxy.txt",
+		"stats":          "account/account_stats.csv",
+		"error":          "account/error_accs.csv",
+		"balances":       "account/balances_accs.csv",
+		"task_results":   "account/points.csv",
+		"eligble":        "account/eligble.csv",
+		"journal":        "account/tx_journal.jsonl",
+		"keystore":       "account/keystore",
+		"vault":          "account/vault.json",
+		"wallets":        "account/wallets.json",
+		"pools":          "account/pools.json",
 	}
 
 	return paths[path]
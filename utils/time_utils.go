@@ -1,10 +1,24 @@
 package utils
 
 import (
+	"context"
 	"lisk/globals"
 	"time"
 )
 
+// SleepContext pauses for d or until ctx is cancelled.
+func SleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 func GetCurrentWeekNumber() int {
 	now := time.Now().UTC()
 	diffDays := int(now.Sub(globals.StartDate).Hours() / 24)
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"lisk/globals"
 	"lisk/httpClient"
 	"lisk/logger"
@@ -14,7 +15,7 @@ func CheckVersion() error {
 	}
 
 	var version models.VersionInfo
-	if err := client.SendJSONRequest(globals.LinkRepo, "GET", nil, &version); err != nil {
+	if err := client.SendJSONRequest(context.Background(), globals.LinkRepo, "GET", nil, &version); err != nil {
 		return err
 	}
 